@@ -0,0 +1,161 @@
+package drone
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/drone/drone-go/drone"
+)
+
+const (
+	pathUserToken = "%s/api/users/%s/token?rotate=true"
+	pathTemplates = "%s/api/templates"
+	pathCron      = "%s/api/repos/%s/%s/cron/%s"
+)
+
+// namespacedTemplate is a template as returned by the templates API. Unlike
+// drone.Template it includes the namespace the template belongs to.
+type namespacedTemplate struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Data      string `json:"data"`
+}
+
+// cronPatch is a cron job update. Unlike drone.CronPatch it can change the
+// schedule of the cron job.
+type cronPatch struct {
+	drone.CronPatch
+	Expr *string `json:"expr,omitempty"`
+}
+
+// Repository sources select how repositories are listed.
+const (
+	repoSourceSync = "sync"
+	repoSourceList = "list"
+	repoSourceAll  = "all"
+)
+
+// repoListAllPageSize is the number of repositories requested per page when
+// listing every repository on the server.
+const repoListAllPageSize = 100
+
+// buildListPageSize is the number of builds requested per page when walking
+// the build history of a repository.
+const buildListPageSize = 50
+
+// isNotFound reports whether err is a 404 response from the Drone API.
+func isNotFound(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "client error 404")
+}
+
+// repoListAll returns every repository in the database by paging through
+// RepoListAll. This is only available to system admins.
+func repoListAll(client drone.Client) ([]*drone.Repo, error) {
+	repos := make([]*drone.Repo, 0)
+
+	for page := 1; ; page++ {
+		resp, err := client.RepoListAll(drone.ListOptions{
+			Page: page,
+			Size: repoListAllPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(resp) == 0 {
+			return repos, nil
+		}
+
+		repos = append(repos, resp...)
+	}
+}
+
+// apiClient calls Drone API endpoints which are not wrapped by drone-go. It
+// mirrors the request handling of the drone-go client.
+type apiClient struct {
+	client *http.Client
+	addr   string
+}
+
+func newAPIClient(uri string, client *http.Client) *apiClient {
+	return &apiClient{client, strings.TrimSuffix(uri, "/")}
+}
+
+// UserTokenRotate generates a new token for the named user and returns the
+// user with its new token.
+func (c *apiClient) UserTokenRotate(login string) (*drone.User, error) {
+	out := new(drone.User)
+	uri := fmt.Sprintf(pathUserToken, c.addr, login)
+	err := c.do(uri, http.MethodPost, nil, out)
+	return out, err
+}
+
+// TemplateListAll returns every template along with its namespace.
+func (c *apiClient) TemplateListAll() ([]*namespacedTemplate, error) {
+	var out []*namespacedTemplate
+	uri := fmt.Sprintf(pathTemplates, c.addr)
+	err := c.do(uri, http.MethodGet, nil, &out)
+	return out, err
+}
+
+// CronUpdate updates the named cron job of a repository.
+func (c *apiClient) CronUpdate(owner, name, cron string, in *cronPatch) (*drone.Cron, error) {
+	out := new(drone.Cron)
+	uri := fmt.Sprintf(pathCron, c.addr, owner, name, cron)
+	err := c.do(uri, http.MethodPatch, in, out)
+	return out, err
+}
+
+// listRepos lists repositories from the given repository source.
+func listRepos(client drone.Client, source string) ([]*drone.Repo, error) {
+	switch source {
+	case repoSourceList:
+		return client.RepoList()
+	case repoSourceAll:
+		return repoListAll(client)
+	default:
+		return client.RepoListSync()
+	}
+}
+
+// helper function to make an http request
+func (c *apiClient) do(rawurl, method string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		encoded, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, rawurl, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 299 {
+		out, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("client error %d: %s", resp.StatusCode, string(out))
+	}
+
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+
+	return nil
+}
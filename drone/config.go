@@ -0,0 +1,182 @@
+package drone
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/jackspirou/syscerts"
+	"golang.org/x/oauth2"
+)
+
+// serverSettings holds the connection settings for a single Drone server.
+type serverSettings struct {
+	Server string
+	Token  string
+}
+
+// defaultTimeout bounds every CRUD operation unless overridden in a
+// resource's timeouts block.
+const defaultTimeout = 5 * time.Minute
+
+// droneMeta is the provider meta passed to every resource and data source.
+// It carries the default server settings, any named server profiles and a
+// cache of authenticated http clients so that each server shares a single
+// connection pool.
+type droneMeta struct {
+	defaults serverSettings
+	profiles map[string]serverSettings
+
+	mu          sync.Mutex
+	httpClients map[string]*http.Client
+}
+
+func newDroneMeta(defaults serverSettings, profiles map[string]serverSettings) *droneMeta {
+	return &droneMeta{
+		defaults:    defaults,
+		profiles:    profiles,
+		httpClients: make(map[string]*http.Client),
+	}
+}
+
+// settings returns the server settings for the named profile. An empty
+// profile name selects the default server.
+func (m *droneMeta) settings(profile string) (serverSettings, error) {
+	if profile == "" {
+		return m.defaults, nil
+	}
+
+	settings, ok := m.profiles[profile]
+	if !ok {
+		return serverSettings{}, fmt.Errorf("Error: Unknown server_profile %q, it must be declared in the provider configuration", profile)
+	}
+
+	return settings, nil
+}
+
+// client returns a client for the named profile whose requests are bound to
+// ctx. The underlying http client is cached and created on first use.
+func (m *droneMeta) client(ctx context.Context, profile string) (drone.Client, error) {
+	settings, httpClient, err := m.httpClient(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return drone.NewClient(settings.Server, httpClient), nil
+}
+
+// httpClient returns an authenticated http client for the named profile
+// whose requests are bound to ctx.
+func (m *droneMeta) httpClient(ctx context.Context, profile string) (serverSettings, *http.Client, error) {
+	settings, err := m.settings(profile)
+	if err != nil {
+		return serverSettings{}, nil, err
+	}
+
+	m.mu.Lock()
+	httpClient, ok := m.httpClients[profile]
+	if !ok {
+		httpClient = newHTTPClient(settings)
+		m.httpClients[profile] = httpClient
+	}
+	m.mu.Unlock()
+
+	return settings, &http.Client{
+		Transport: &contextTransport{ctx: ctx, base: httpClient.Transport},
+	}, nil
+}
+
+// clientFor returns the client for the server_profile selected by a
+// resource or data source.
+func (m *droneMeta) clientFor(ctx context.Context, d *schema.ResourceData) (drone.Client, error) {
+	return m.client(ctx, d.Get("server_profile").(string))
+}
+
+// apiClientFor returns the api client for the server_profile selected by a
+// resource or data source.
+func (m *droneMeta) apiClientFor(ctx context.Context, d *schema.ResourceData) (*apiClient, error) {
+	settings, httpClient, err := m.httpClient(ctx, d.Get("server_profile").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	return newAPIClient(settings.Server, httpClient), nil
+}
+
+// contextTransport binds every request to a context, since drone-go builds
+// its requests without one. Cancelling the context aborts requests in
+// flight.
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
+
+func newHTTPClient(settings serverSettings) *http.Client {
+	config := new(oauth2.Config)
+
+	certs := syscerts.SystemRootsPool()
+	tlsConfig := &tls.Config{
+		RootCAs:            certs,
+		InsecureSkipVerify: false,
+	}
+
+	auther := config.Client(
+		oauth2.NoContext,
+		&oauth2.Token{AccessToken: settings.Token},
+	)
+
+	trans, _ := auther.Transport.(*oauth2.Transport)
+	trans.Base = &http.Transport{
+		TLSClientConfig: tlsConfig,
+		Proxy:           http.ProxyFromEnvironment,
+	}
+
+	return auther
+}
+
+func serverProfileSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "Name of a `server_profile` block in the provider configuration to use instead of the default server. To import from a profile, prefix the import ID with the profile name and a colon, e.g. `staging:octocat/hello-world`",
+	}
+}
+
+// importProfile handles the optional profile: prefix of an import ID, which
+// selects the server_profile the object is imported from. The prefix is
+// removed from the ID.
+func importProfile(d *schema.ResourceData, m interface{}) error {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || strings.Contains(parts[0], "/") {
+		return nil
+	}
+
+	if _, err := m.(*droneMeta).settings(parts[0]); err != nil {
+		return err
+	}
+
+	d.SetId(parts[1])
+	d.Set("server_profile", parts[0])
+
+	return nil
+}
+
+func defaultResourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultTimeout),
+		Read:   schema.DefaultTimeout(defaultTimeout),
+		Update: schema.DefaultTimeout(defaultTimeout),
+		Delete: schema.DefaultTimeout(defaultTimeout),
+	}
+}
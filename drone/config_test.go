@@ -0,0 +1,108 @@
+package drone
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDroneMetaUnknownProfile(t *testing.T) {
+	meta := newDroneMeta(serverSettings{Server: "http://drone"}, nil)
+
+	if _, err := meta.client(context.Background(), "missing"); err == nil {
+		t.Fatal("expected an error for an undeclared server_profile")
+	}
+}
+
+func TestDroneMetaCachesHTTPClient(t *testing.T) {
+	meta := newDroneMeta(
+		serverSettings{Server: "http://drone"},
+		map[string]serverSettings{"external": {Server: "http://external"}},
+	)
+
+	for _, profile := range []string{"", "external", ""} {
+		if _, err := meta.client(context.Background(), profile); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	if len(meta.httpClients) != 2 {
+		t.Fatalf("expected 2 cached http clients, got %d", len(meta.httpClients))
+	}
+}
+
+func TestDroneMetaClientHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	meta := newDroneMeta(serverSettings{Server: server.URL, Token: "token"}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	client, err := meta.client(ctx, "")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Self()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context deadline exceeded, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not cancelled by its context")
+	}
+}
+
+func TestImportProfile(t *testing.T) {
+	meta := newDroneMeta(
+		serverSettings{Server: "http://drone"},
+		map[string]serverSettings{"external": {Server: "http://external"}},
+	)
+
+	for _, tc := range []struct {
+		id      string
+		profile string
+		rest    string
+		err     bool
+	}{
+		{id: "octocat/hello-world/secret", rest: "octocat/hello-world/secret"},
+		{id: "external:octocat/hello-world/secret", profile: "external", rest: "octocat/hello-world/secret"},
+		{id: "external:octocat", profile: "external", rest: "octocat"},
+		{id: "missing:octocat/hello-world/secret", err: true},
+	} {
+		d := schema.TestResourceDataRaw(t, resourceSecret().Schema, map[string]interface{}{})
+		d.SetId(tc.id)
+
+		err := importProfile(d, meta)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: expected an error for an undeclared server_profile", tc.id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: err: %s", tc.id, err)
+		}
+
+		if profile := d.Get("server_profile").(string); profile != tc.profile || d.Id() != tc.rest {
+			t.Errorf("%s: expected profile %q and ID %s, got profile %q and ID %s", tc.id, tc.profile, tc.rest, profile, d.Id())
+		}
+	}
+}
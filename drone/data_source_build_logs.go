@@ -0,0 +1,246 @@
+package drone
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"terraform-provider-drone/drone/utils"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ansiEscape matches ANSI escape sequences such as colors and cursor
+// movements.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+func dataSourceBuildLogs() *schema.Resource {
+	return &schema.Resource{
+		Description: "Data source for retrieving the logs of a step of a Drone build, along with the status and exit code of every step in its stage",
+		ReadContext: dataSourceBuildLogsRead,
+		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile("^[^/ ]+/[^/ ]+$"),
+					"Invalid repository (e.g. octocat/hello-world)",
+				),
+			},
+			"build_number": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"stage_number": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				ExactlyOneOf: []string{"stage_number", "stage_name"},
+			},
+			"stage_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"stage_number", "stage_name"},
+			},
+			"step_number": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				ExactlyOneOf: []string{"step_number", "step_name"},
+			},
+			"step_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"step_number", "step_name"},
+			},
+			"tail": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Only return this many lines from the end of the log",
+			},
+			"strip_ansi": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Remove ANSI escape sequences, such as colors, from the log",
+			},
+			"lines": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Log lines of the step",
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Log lines of the step joined into a single string",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the step",
+			},
+			"exit_code": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Exit code of the step",
+			},
+			"steps": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Every step of the stage",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"number": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"exit_code": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceBuildLogsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	repository := d.Get("repository").(string)
+
+	owner, repo, err := utils.ParseRepo(repository)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	number := d.Get("build_number").(int)
+
+	build, err := client.Build(owner, repo, number)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Failed to read Drone build: %s#%d", repository, number),
+			Detail:   err.Error(),
+		})
+
+		return diags
+	}
+
+	stage, err := buildStage(build, d.Get("stage_number").(int), d.Get("stage_name").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	step, err := stageStep(stage, d.Get("step_number").(int), d.Get("step_name").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	logs, err := client.Logs(owner, repo, number, stage.Number, step.Number)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Failed to read logs of Drone build: %s#%d, stage %d, step %d", repository, number, stage.Number, step.Number),
+			Detail:   err.Error(),
+		})
+
+		return diags
+	}
+
+	lines := logLines(logs, d.Get("strip_ansi").(bool))
+	if tail := d.Get("tail").(int); tail > 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+
+	steps := make([]map[string]interface{}, 0, len(stage.Steps))
+	for _, s := range stage.Steps {
+		steps = append(steps, map[string]interface{}{
+			"number":    s.Number,
+			"name":      s.Name,
+			"status":    s.Status,
+			"exit_code": s.ExitCode,
+		})
+	}
+
+	d.Set("stage_number", stage.Number)
+	d.Set("stage_name", stage.Name)
+	d.Set("step_number", step.Number)
+	d.Set("step_name", step.Name)
+	d.Set("lines", lines)
+	d.Set("content", strings.Join(lines, "\n"))
+	d.Set("status", step.Status)
+	d.Set("exit_code", step.ExitCode)
+	d.Set("steps", steps)
+
+	d.SetId(fmt.Sprintf("%s/%d/%d/%d", repository, number, stage.Number, step.Number))
+
+	return diags
+}
+
+// stageStep returns the step of stage with the given number, or with the
+// given name when number is zero.
+func stageStep(stage *drone.Stage, number int, name string) (*drone.Step, error) {
+	for _, step := range stage.Steps {
+		if number != 0 && step.Number == number {
+			return step, nil
+		}
+		if number == 0 && step.Name == name {
+			return step, nil
+		}
+	}
+
+	if number != 0 {
+		return nil, fmt.Errorf("Error: Stage %s has no step %d", stage.Name, number)
+	}
+
+	return nil, fmt.Errorf("Error: Stage %s has no step named %q", stage.Name, name)
+}
+
+// logLines splits log output into lines. Drone stores output in chunks which
+// usually, but not always, end with a newline.
+func logLines(logs []*drone.Line, stripANSI bool) []string {
+	var buf strings.Builder
+	for _, line := range logs {
+		buf.WriteString(line.Message)
+	}
+
+	out := buf.String()
+	if stripANSI {
+		out = ansiEscape.ReplaceAllString(out, "")
+	}
+
+	out = strings.TrimSuffix(strings.ReplaceAll(out, "\r\n", "\n"), "\n")
+	if out == "" {
+		return []string{}
+	}
+
+	return strings.Split(out, "\n")
+}
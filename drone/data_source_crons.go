@@ -0,0 +1,227 @@
+package drone
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"terraform-provider-drone/drone/utils"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceCrons() *schema.Resource {
+	return &schema.Resource{
+		Description: "Data source for retrieving the cron jobs of a repository, or of every repository on the server (admin only)",
+		ReadContext: dataSourceCronsRead,
+		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
+			"repository": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile("^[^/ ]+/[^/ ]+$"),
+					"Invalid repository (e.g. octocat/hello-world)",
+				),
+				Description: "Return the cron jobs of this repository. Exactly one of `repository` or `all_repositories = true` must be set",
+			},
+			"all_repositories": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Return the cron jobs of every active repository on the server (admin only)",
+			},
+			"include_disabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Return disabled cron jobs",
+			},
+			"next_after": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				Description:  "Only return cron jobs next executed at or after this RFC 3339 time",
+			},
+			"next_before": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				Description:  "Only return cron jobs next executed before this RFC 3339 time",
+			},
+			"crons": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Cron jobs ordered by their next execution time",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"repository": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"expr": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"branch": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"event": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"target": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"disabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"next": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"prev": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// repoCron is a cron job along with the repository it belongs to.
+type repoCron struct {
+	repository string
+	cron       *drone.Cron
+}
+
+func dataSourceCronsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	// all_repositories = false counts as set, so the arguments are checked
+	// here rather than with ExactlyOneOf.
+	repository, ok := d.GetOk("repository")
+	all := d.Get("all_repositories").(bool)
+	if ok == all {
+		return diag.Errorf("Error: Exactly one of repository or all_repositories = true must be set")
+	}
+
+	var repos []*drone.Repo
+	if ok {
+		owner, repo, err := utils.ParseRepo(repository.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		repos = []*drone.Repo{{Namespace: owner, Name: repo, Slug: repository.(string), Active: true}}
+	} else {
+		repos, err = repoListAll(client)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Failed to retrieve repositories",
+				Detail:   err.Error(),
+			})
+
+			return diags
+		}
+	}
+
+	includeDisabled := d.Get("include_disabled").(bool)
+
+	var after, before int64
+	if v, ok := d.GetOk("next_after"); ok {
+		t, _ := time.Parse(time.RFC3339, v.(string))
+		after = t.Unix()
+	}
+	if v, ok := d.GetOk("next_before"); ok {
+		t, _ := time.Parse(time.RFC3339, v.(string))
+		before = t.Unix()
+	}
+
+	crons := make([]repoCron, 0)
+
+	for _, repo := range repos {
+		// Only active repositories can have cron jobs.
+		if !repo.Active {
+			continue
+		}
+
+		resp, err := client.CronList(repo.Namespace, repo.Name)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Failed to retrieve Drone Crons: %s", repo.Slug),
+				Detail:   err.Error(),
+			})
+
+			return diags
+		}
+
+		for _, cron := range resp {
+			if !includeDisabled && cron.Disabled {
+				continue
+			}
+			if after != 0 && cron.Next < after {
+				continue
+			}
+			if before != 0 && cron.Next >= before {
+				continue
+			}
+
+			crons = append(crons, repoCron{repository: repo.Slug, cron: cron})
+		}
+	}
+
+	sort.SliceStable(crons, func(i, j int) bool {
+		if crons[i].cron.Next != crons[j].cron.Next {
+			return crons[i].cron.Next < crons[j].cron.Next
+		}
+		if crons[i].repository != crons[j].repository {
+			return crons[i].repository < crons[j].repository
+		}
+		return crons[i].cron.Name < crons[j].cron.Name
+	})
+
+	id := make([]string, 0)
+	results := make([]map[string]interface{}, 0)
+
+	for _, c := range crons {
+		id = append(id, fmt.Sprintf("%s/%s", c.repository, c.cron.Name))
+		results = append(results, map[string]interface{}{
+			"repository": c.repository,
+			"name":       c.cron.Name,
+			"expr":       c.cron.Expr,
+			"branch":     c.cron.Branch,
+			"event":      c.cron.Event,
+			"target":     c.cron.Target,
+			"disabled":   c.cron.Disabled,
+			"next":       c.cron.Next,
+			"prev":       c.cron.Prev,
+		})
+	}
+
+	d.Set("crons", results)
+
+	d.SetId(utils.BuildChecksumID(id))
+
+	return diags
+}
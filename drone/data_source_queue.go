@@ -0,0 +1,195 @@
+package drone
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"terraform-provider-drone/drone/utils"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceQueue() *schema.Resource {
+	return &schema.Resource{
+		Description: "Data source for retrieving the pending and running stages of the Drone build queue (admin only)",
+		ReadContext: dataSourceQueueRead,
+		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
+			"pending_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of stages waiting for a runner",
+			},
+			"running_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of stages being executed by a runner",
+			},
+			"stages": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"repository": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"build_number": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"kind": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"os": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"arch": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"variant": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"kernel": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"machine": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"labels": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"created": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"started": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceQueueRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	stages, err := client.IncompleteV2()
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Failed to retrieve the Drone queue",
+			Detail:   err.Error(),
+		})
+
+		return diags
+	}
+
+	sort.SliceStable(stages, func(i, j int) bool {
+		if stages[i].RepoSlug != stages[j].RepoSlug {
+			return stages[i].RepoSlug < stages[j].RepoSlug
+		}
+		return stages[i].BuildNumber < stages[j].BuildNumber
+	})
+
+	// Stage labels are not part of the queue listing, so they are read
+	// from the builds the stages belong to.
+	builds := make(map[string]*drone.Build)
+
+	id := make([]string, 0)
+	pending := 0
+	running := 0
+	results := make([]map[string]interface{}, 0)
+
+	for _, stage := range stages {
+		key := fmt.Sprintf("%s/%d", stage.RepoSlug, stage.BuildNumber)
+
+		build, ok := builds[key]
+		if !ok {
+			build, err = client.Build(stage.RepoNamespace, stage.RepoName, int(stage.BuildNumber))
+			if err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  fmt.Sprintf("Failed to retrieve Drone build: %s", key),
+					Detail:   err.Error(),
+				})
+
+				return diags
+			}
+			builds[key] = build
+		}
+
+		labels := make(map[string]string)
+		for _, s := range build.Stages {
+			if s.Name == stage.StageName {
+				labels = s.Labels
+				break
+			}
+		}
+
+		switch stage.StageStatus {
+		case drone.StatusPending:
+			pending++
+		case drone.StatusRunning:
+			running++
+		}
+
+		id = append(id, fmt.Sprintf("%s/%s/%s", key, stage.StageName, stage.StageStatus))
+		results = append(results, map[string]interface{}{
+			"repository":   stage.RepoSlug,
+			"build_number": stage.BuildNumber,
+			"name":         stage.StageName,
+			"kind":         stage.StageKind,
+			"type":         stage.StageType,
+			"status":       stage.StageStatus,
+			"os":           stage.StageOS,
+			"arch":         stage.StageArch,
+			"variant":      stage.StageVariant,
+			"kernel":       stage.StageKernel,
+			"machine":      stage.StageMachine,
+			"labels":       labels,
+			"created":      stage.BuildCreated,
+			"started":      stage.StageStarted,
+		})
+	}
+
+	d.Set("pending_count", pending)
+	d.Set("running_count", running)
+	d.Set("stages", results)
+
+	d.SetId(utils.BuildChecksumID(id))
+
+	return diags
+}
@@ -6,7 +6,6 @@ import (
 	"regexp"
 	"terraform-provider-drone/drone/utils"
 
-	"github.com/drone/drone-go/drone"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -53,6 +52,11 @@ func dataSourceRepo() *schema.Resource {
 					"Invalid repository (e.g. octocat/hello-world)",
 				),
 			},
+			"server_profile": serverProfileSchema(),
+			"throttle": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
 			"timeout": {
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -70,7 +74,10 @@ func dataSourceRepo() *schema.Resource {
 }
 
 func dataSourceRepoRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -104,6 +111,7 @@ func dataSourceRepoRead(ctx context.Context, d *schema.ResourceData, m interface
 	d.Set("ignore_forks", repo.IgnoreForks)
 	d.Set("ignore_pulls", repo.IgnorePulls)
 	d.Set("protected", repo.Protected)
+	d.Set("throttle", repo.Throttle)
 	d.Set("timeout", repo.Timeout)
 	d.Set("trusted", repo.Trusted)
 	d.Set("visibility", repo.Visibility)
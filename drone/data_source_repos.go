@@ -2,13 +2,14 @@ package drone
 
 import (
 	"context"
+	"regexp"
 	"sort"
 
 	"terraform-provider-drone/drone/utils"
 
-	"github.com/drone/drone-go/drone"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceRepos() *schema.Resource {
@@ -16,6 +17,40 @@ func dataSourceRepos() *schema.Resource {
 		Description: "Data source for retrieving all repositories to which the user has explicit access in the host system",
 		ReadContext: dataSourceReposRead,
 		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
+			"source": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  repoSourceSync,
+				ValidateFunc: validation.StringInSlice([]string{
+					repoSourceSync,
+					repoSourceList,
+					repoSourceAll,
+				}, false),
+				Description: "How repositories are listed: `sync` refreshes the user's repositories from the host system, `list` returns the user's repositories without syncing, and `all` returns every repository on the server (admin only)",
+			},
+			"namespace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return repositories in this namespace",
+			},
+			"active_only": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return repositories which are active in Drone",
+			},
+			"visibility": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(repoVisibilities, false),
+				Description:  "Only return repositories with this visibility",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only return repositories whose name matches this regular expression",
+			},
 			"repositories": {
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
@@ -23,17 +58,68 @@ func dataSourceRepos() *schema.Resource {
 				},
 				Computed: true,
 			},
+			"repos": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"slug": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"namespace": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"active": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"visibility": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"trusted": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"protected": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"configuration": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"default_branch": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"timeout": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
 func dataSourceReposRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	repos, err := client.RepoListSync()
+	repos, err := listRepos(client, d.Get("source").(string))
 	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
@@ -44,16 +130,55 @@ func dataSourceReposRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diags
 	}
 
+	namespace := d.Get("namespace").(string)
+	activeOnly := d.Get("active_only").(bool)
+	visibility := d.Get("visibility").(string)
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].Slug < repos[j].Slug
+	})
+
 	id := make([]string, 0)
 	slugs := make([]string, 0)
+	results := make([]map[string]interface{}, 0)
 
 	for _, repo := range repos {
+		if namespace != "" && repo.Namespace != namespace {
+			continue
+		}
+		if activeOnly && !repo.Active {
+			continue
+		}
+		if visibility != "" && repo.Visibility != visibility {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(repo.Name) {
+			continue
+		}
+
 		id = append(id, repo.Slug)
 		slugs = append(slugs, repo.Slug)
+		results = append(results, map[string]interface{}{
+			"slug":           repo.Slug,
+			"namespace":      repo.Namespace,
+			"name":           repo.Name,
+			"active":         repo.Active,
+			"visibility":     repo.Visibility,
+			"trusted":        repo.Trusted,
+			"protected":      repo.Protected,
+			"configuration":  repo.Config,
+			"default_branch": repo.Branch,
+			"timeout":        repo.Timeout,
+		})
 	}
 
-	sort.Strings(slugs)
 	d.Set("repositories", slugs)
+	d.Set("repos", results)
 
 	d.SetId(utils.BuildChecksumID(id))
 
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/drone/drone-go/drone"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -14,6 +13,7 @@ func dataSourceTemplate() *schema.Resource {
 		Description: "Data source for retrieving a Drone template",
 		ReadContext: dataSourceTemplateRead,
 		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
@@ -31,7 +31,10 @@ func dataSourceTemplate() *schema.Resource {
 }
 
 func dataSourceTemplateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
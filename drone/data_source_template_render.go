@@ -0,0 +1,73 @@
+package drone
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"terraform-provider-drone/drone/utils"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceTemplateRender() *schema.Resource {
+	return &schema.Resource{
+		Description: "Data source for rendering a Drone template locally with the given inputs, the way Drone expands it when a pipeline extends it",
+		ReadContext: dataSourceTemplateRenderRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateTemplateName,
+				Description:  "Template name, its extension selects the template format",
+			},
+			"data": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Template content",
+			},
+			"inputs": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "{}",
+				ValidateFunc: validation.StringIsJSON,
+				Description:  "JSON object of template inputs, as passed with `with` in a pipeline extending the template, e.g. `jsonencode({ image = \"golang\" })`",
+			},
+			"rendered": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Rendered pipeline documents",
+			},
+		},
+	}
+}
+
+func dataSourceTemplateRenderRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	name := d.Get("name").(string)
+
+	var inputs map[string]interface{}
+	if err := json.Unmarshal([]byte(d.Get("inputs").(string)), &inputs); err != nil {
+		return diag.Errorf("Error: inputs must be a JSON object: %s", err)
+	}
+
+	rendered, err := renderTemplate(name, d.Get("data").(string), inputs)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Failed to render template %s", name),
+			Detail:   err.Error(),
+		})
+
+		return diags
+	}
+
+	d.Set("rendered", rendered)
+	d.SetId(utils.BuildChecksumID([]string{name, rendered}))
+
+	return diags
+}
@@ -2,23 +2,33 @@ package drone
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"sort"
 
 	"terraform-provider-drone/drone/utils"
 
-	"github.com/drone/drone-go/drone"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceTemplates() *schema.Resource {
 	return &schema.Resource{
-		Description: "Data source for retrieving all Drone templates in a namespace",
+		Description: "Data source for retrieving Drone templates in a namespace, or in every namespace",
 		ReadContext: dataSourceTemplatesRead,
 		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
 			"namespace": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return templates in this namespace. When omitted, templates in every namespace are returned, which requires an admin token",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only return templates whose name matches this regular expression",
 			},
 			"names": {
 				Type: schema.TypeList,
@@ -27,18 +37,35 @@ func dataSourceTemplates() *schema.Resource {
 				},
 				Computed: true,
 			},
+			"templates": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"namespace": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"data": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
 func dataSourceTemplatesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
-
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	namespace := d.Get("namespace").(string)
-	templates, err := client.TemplateList(namespace)
+	templates, err := listTemplates(ctx, d, m)
 	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
@@ -49,18 +76,76 @@ func dataSourceTemplatesRead(ctx context.Context, d *schema.ResourceData, m inte
 		return diags
 	}
 
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Namespace != templates[j].Namespace {
+			return templates[i].Namespace < templates[j].Namespace
+		}
+		return templates[i].Name < templates[j].Name
+	})
+
 	id := make([]string, 0)
 	names := make([]string, 0)
+	results := make([]map[string]interface{}, 0)
 
 	for _, template := range templates {
-		id = append(id, template.Name)
+		if nameRegex != nil && !nameRegex.MatchString(template.Name) {
+			continue
+		}
+
+		id = append(id, fmt.Sprintf("%s/%s", template.Namespace, template.Name))
 		names = append(names, template.Name)
+		results = append(results, map[string]interface{}{
+			"namespace": template.Namespace,
+			"name":      template.Name,
+			"data":      template.Data,
+		})
 	}
 
-	sort.Strings(names)
 	d.Set("names", names)
+	d.Set("templates", results)
 
 	d.SetId(utils.BuildChecksumID(id))
 
 	return diags
 }
+
+// listTemplates lists the templates in the configured namespace, or in every
+// namespace when it is omitted.
+func listTemplates(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*namespacedTemplate, error) {
+	namespace := d.Get("namespace").(string)
+
+	if namespace == "" {
+		api, err := m.(*droneMeta).apiClientFor(ctx, d)
+		if err != nil {
+			return nil, err
+		}
+
+		return api.TemplateListAll()
+	}
+
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := client.TemplateList(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]*namespacedTemplate, 0, len(list))
+	for _, template := range list {
+		templates = append(templates, &namespacedTemplate{
+			Namespace: namespace,
+			Name:      template.Name,
+			Data:      template.Data,
+		})
+	}
+
+	return templates, nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/drone/drone-go/drone"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -34,12 +33,16 @@ func dataSourceUserSelf() *schema.Resource {
 				Type:     schema.TypeBool,
 				Computed: true,
 			},
+			"server_profile": serverProfileSchema(),
 		},
 	}
 }
 
 func dataSourceUserSelfRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -2,13 +2,15 @@ package drone
 
 import (
 	"context"
+	"regexp"
 	"sort"
+	"time"
 
 	"terraform-provider-drone/drone/utils"
 
-	"github.com/drone/drone-go/drone"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceUsers() *schema.Resource {
@@ -16,6 +18,34 @@ func dataSourceUsers() *schema.Resource {
 		Description: "Data source for retrieving all Drone users",
 		ReadContext: dataSourceUsersRead,
 		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
+			"active": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return users whose active flag matches this value",
+			},
+			"admin": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return users whose admin flag matches this value",
+			},
+			"machine": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return users whose machine flag matches this value",
+			},
+			"login_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only return users whose login matches this regular expression",
+			},
+			"inactive_since": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+				Description:  "Only return users who have not logged in since this RFC3339 timestamp, including users who never logged in",
+			},
 			"logins": {
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
@@ -23,12 +53,55 @@ func dataSourceUsers() *schema.Resource {
 				},
 				Computed: true,
 			},
+			"users": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"login": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"email": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"active": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"admin": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"machine": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"created": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"last_login": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"syncing": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
 func dataSourceUsersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -44,16 +117,63 @@ func dataSourceUsersRead(ctx context.Context, d *schema.ResourceData, m interfac
 		return diags
 	}
 
+	config := d.GetRawConfig()
+
+	var loginRegex *regexp.Regexp
+	if v, ok := d.GetOk("login_regex"); ok {
+		loginRegex = regexp.MustCompile(v.(string))
+	}
+
+	var inactiveSince int64
+	if v, ok := d.GetOk("inactive_since"); ok {
+		since, err := time.Parse(time.RFC3339, v.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		inactiveSince = since.Unix()
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Login < users[j].Login
+	})
+
 	id := make([]string, 0)
 	logins := make([]string, 0)
+	results := make([]map[string]interface{}, 0)
 
 	for _, user := range users {
+		if !config.GetAttr("active").IsNull() && user.Active != d.Get("active").(bool) {
+			continue
+		}
+		if !config.GetAttr("admin").IsNull() && user.Admin != d.Get("admin").(bool) {
+			continue
+		}
+		if !config.GetAttr("machine").IsNull() && user.Machine != d.Get("machine").(bool) {
+			continue
+		}
+		if loginRegex != nil && !loginRegex.MatchString(user.Login) {
+			continue
+		}
+		if inactiveSince != 0 && user.LastLogin >= inactiveSince {
+			continue
+		}
+
 		id = append(id, user.Login)
 		logins = append(logins, user.Login)
+		results = append(results, map[string]interface{}{
+			"login":      user.Login,
+			"email":      user.Email,
+			"active":     user.Active,
+			"admin":      user.Admin,
+			"machine":    user.Machine,
+			"created":    user.Created,
+			"last_login": user.LastLogin,
+			"syncing":    user.Syncing,
+		})
 	}
 
-	sort.Strings(logins)
 	d.Set("logins", logins)
+	d.Set("users", results)
 
 	d.SetId(utils.BuildChecksumID(id))
 
@@ -0,0 +1,99 @@
+package drone
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dockerConfigSchema returns the schema of the docker_config blocks of the
+// secret resources. Each block holds the credentials of one registry.
+func dockerConfigSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeList,
+		Optional:     true,
+		ExactlyOneOf: []string{"value", "docker_config"},
+		Description:  "Registry credentials to store as a `.dockerconfigjson` secret, for use with `image_pull_secrets`. Conflicts with `value`",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"registry": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Registry address, such as `docker.io` or `https://index.docker.io/v1/`",
+				},
+				"username": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"password": {
+					Type:      schema.TypeString,
+					Required:  true,
+					Sensitive: true,
+				},
+				"email": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+type dockerAuth struct {
+	Auth  string `json:"auth"`
+	Email string `json:"email,omitempty"`
+}
+
+type dockerConfig struct {
+	Auths map[string]dockerAuth `json:"auths"`
+}
+
+// secretValue returns the value of a secret resource, which is either set
+// directly or generated from its docker_config blocks.
+func secretValue(d resourceGetter) string {
+	entries, ok := d.GetOk("docker_config")
+	if !ok {
+		return d.Get("value").(string)
+	}
+
+	config := dockerConfig{Auths: make(map[string]dockerAuth)}
+	for _, entry := range entries.([]interface{}) {
+		entry := entry.(map[string]interface{})
+		credentials := fmt.Sprintf("%s:%s", entry["username"], entry["password"])
+
+		config.Auths[entry["registry"].(string)] = dockerAuth{
+			Auth:  base64.StdEncoding.EncodeToString([]byte(credentials)),
+			Email: entry["email"].(string),
+		}
+	}
+
+	// Marshalling a struct of strings cannot fail, and map keys are sorted,
+	// so the value only changes when the credentials do.
+	value, _ := json.Marshal(config)
+
+	return string(value)
+}
+
+// secretCustomizeDiff checks during plan that every docker_config block names
+// a different registry.
+func secretCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	registries := make(map[string]bool)
+
+	for _, entry := range d.Get("docker_config").([]interface{}) {
+		registry := entry.(map[string]interface{})["registry"].(string)
+		if registry == "" {
+			// Not known until apply.
+			continue
+		}
+
+		if registries[registry] {
+			return fmt.Errorf("Error: Registry %s is configured in more than one docker_config block", registry)
+		}
+		registries[registry] = true
+	}
+
+	return nil
+}
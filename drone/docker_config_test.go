@@ -0,0 +1,44 @@
+package drone
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSecretValue(t *testing.T) {
+	for _, tc := range []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{
+			raw:      map[string]interface{}{"name": "secret", "value": "thisissecret"},
+			expected: "thisissecret",
+		},
+		{
+			raw: map[string]interface{}{
+				"name": "dockerconfigjson",
+				"docker_config": []interface{}{
+					map[string]interface{}{
+						"registry": "https://index.docker.io/v1/",
+						"username": "octocat",
+						"password": "correct-horse",
+					},
+					map[string]interface{}{
+						"registry": "ghcr.io",
+						"username": "octocat",
+						"password": "battery-staple",
+						"email":    "octocat@github.com",
+					},
+				},
+			},
+			expected: `{"auths":{"ghcr.io":{"auth":"b2N0b2NhdDpiYXR0ZXJ5LXN0YXBsZQ==","email":"octocat@github.com"},"https://index.docker.io/v1/":{"auth":"b2N0b2NhdDpjb3JyZWN0LWhvcnNl"}}}`,
+		},
+	} {
+		d := schema.TestResourceDataRaw(t, resourceOrgSecret().Schema, tc.raw)
+
+		if value := secretValue(d); value != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, value)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package drone
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// exportSecretPlaceholder is written in place of organization secret values,
+// which cannot be read back from Drone.
+const exportSecretPlaceholder = "REPLACE_ME"
+
+const exportHeader = `# Generated by terraform-provider-drone export.
+#
+# Drone does not return secret values, so every drone_orgsecret value is a
+# placeholder and changes to it are ignored. Set the real value and remove
+# the lifecycle block to manage it with Terraform.
+
+`
+
+// ExportOptions selects the Drone server and the repositories written by
+// Export.
+type ExportOptions struct {
+	Server string
+	Token  string
+
+	// Source selects how repositories are listed: sync, list or all. It
+	// defaults to all, which requires an admin token. Organization secrets,
+	// templates and users are only exported with all.
+	Source string
+
+	// Warnings receives a line for every object which cannot be exported.
+	Warnings io.Writer
+}
+
+// Export walks the Drone server and writes Terraform configuration for its
+// active repositories and their cron jobs, organization secrets, templates
+// and users to w. Every resource is followed by an import block so that the
+// existing objects are adopted by a single apply.
+func Export(w io.Writer, opts ExportOptions) error {
+	source := opts.Source
+	if source == "" {
+		source = repoSourceAll
+	}
+
+	switch source {
+	case repoSourceSync, repoSourceList, repoSourceAll:
+	default:
+		return fmt.Errorf("Error: Invalid source %q, it must be one of %s, %s or %s", source, repoSourceSync, repoSourceList, repoSourceAll)
+	}
+
+	httpClient := newHTTPClient(serverSettings{Server: opts.Server, Token: opts.Token})
+
+	e := newExporter(
+		drone.NewClient(opts.Server, httpClient),
+		newAPIClient(opts.Server, httpClient),
+	)
+
+	if err := e.export(w, source); err != nil {
+		return err
+	}
+
+	if opts.Warnings != nil {
+		for _, skipped := range e.skipped {
+			fmt.Fprintf(opts.Warnings, "Warning: Skipped %s\n", skipped)
+		}
+	}
+
+	return nil
+}
+
+var exportLabelInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// exporter builds the exported configuration in memory.
+type exporter struct {
+	client drone.Client
+	api    *apiClient
+
+	file   *hclwrite.File
+	labels map[string]map[string]bool
+
+	// skipped describes the objects which the provider cannot manage.
+	skipped []string
+}
+
+func newExporter(client drone.Client, api *apiClient) *exporter {
+	return &exporter{
+		client: client,
+		api:    api,
+		file:   hclwrite.NewEmptyFile(),
+		labels: make(map[string]map[string]bool),
+	}
+}
+
+func (e *exporter) export(w io.Writer, source string) error {
+	repos, err := listRepos(e.client, source)
+	if err != nil {
+		return fmt.Errorf("Error: Failed to list repositories: %w", err)
+	}
+
+	active := make([]*drone.Repo, 0)
+	for _, repo := range repos {
+		if repo.Active {
+			active = append(active, repo)
+		}
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].Slug < active[j].Slug
+	})
+
+	for _, repo := range active {
+		e.repo(repo)
+	}
+
+	for _, repo := range active {
+		crons, err := e.client.CronList(repo.Namespace, repo.Name)
+		if err != nil {
+			return fmt.Errorf("Error: Failed to list cron jobs of %s: %w", repo.Slug, err)
+		}
+
+		sort.Slice(crons, func(i, j int) bool {
+			return crons[i].Name < crons[j].Name
+		})
+
+		for _, cron := range crons {
+			e.cron(repo, cron)
+		}
+	}
+
+	// Organization secrets, templates and users can only be listed by an
+	// admin, so they are only exported along with every repository.
+	if source == repoSourceAll {
+		if err := e.exportAdmin(); err != nil {
+			return err
+		}
+	} else {
+		e.skipped = append(e.skipped, fmt.Sprintf(
+			"organization secrets, templates and users, listing them requires an admin token and the %s source",
+			repoSourceAll,
+		))
+	}
+
+	if _, err := io.WriteString(w, exportHeader); err != nil {
+		return err
+	}
+
+	_, err = e.file.WriteTo(w)
+	return err
+}
+
+// exportAdmin adds the organization secrets, templates and users of the
+// server, which are only listed for admins.
+func (e *exporter) exportAdmin() error {
+	secrets, err := e.client.OrgSecretListAll()
+	if err != nil {
+		return fmt.Errorf("Error: Failed to list organization secrets: %w", err)
+	}
+
+	sort.Slice(secrets, func(i, j int) bool {
+		if secrets[i].Namespace != secrets[j].Namespace {
+			return secrets[i].Namespace < secrets[j].Namespace
+		}
+		return secrets[i].Name < secrets[j].Name
+	})
+
+	for _, secret := range secrets {
+		e.orgSecret(secret)
+	}
+
+	templates, err := e.api.TemplateListAll()
+	if err != nil {
+		return fmt.Errorf("Error: Failed to list templates: %w", err)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Namespace != templates[j].Namespace {
+			return templates[i].Namespace < templates[j].Namespace
+		}
+		return templates[i].Name < templates[j].Name
+	})
+
+	for _, template := range templates {
+		e.template(template)
+	}
+
+	users, err := e.client.UserList()
+	if err != nil {
+		return fmt.Errorf("Error: Failed to list users: %w", err)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Login < users[j].Login
+	})
+
+	for _, user := range users {
+		e.user(user)
+	}
+
+	return nil
+}
+
+func (e *exporter) repo(repo *drone.Repo) {
+	body := e.resource("drone_repo", repo.Slug, repo.Slug)
+
+	body.SetAttributeValue("repository", cty.StringVal(repo.Slug))
+	body.SetAttributeValue("cancel_pulls", cty.BoolVal(repo.CancelPulls))
+	body.SetAttributeValue("cancel_push", cty.BoolVal(repo.CancelPush))
+	body.SetAttributeValue("cancel_running", cty.BoolVal(repo.CancelRunning))
+	if repo.Config != "" {
+		body.SetAttributeValue("configuration", cty.StringVal(repo.Config))
+	}
+	body.SetAttributeValue("ignore_forks", cty.BoolVal(repo.IgnoreForks))
+	body.SetAttributeValue("ignore_pulls", cty.BoolVal(repo.IgnorePulls))
+	body.SetAttributeValue("protected", cty.BoolVal(repo.Protected))
+	body.SetAttributeValue("throttle", cty.NumberIntVal(repo.Throttle))
+	if repo.Timeout > 0 {
+		body.SetAttributeValue("timeout", cty.NumberIntVal(repo.Timeout))
+	}
+	body.SetAttributeValue("trusted", cty.BoolVal(repo.Trusted))
+	if repo.Visibility != "" {
+		body.SetAttributeValue("visibility", cty.StringVal(repo.Visibility))
+	}
+}
+
+func (e *exporter) cron(repo *drone.Repo, cron *drone.Cron) {
+	if !isCronExpr(cron.Expr) {
+		e.skipped = append(e.skipped, fmt.Sprintf(
+			"cron job %s/%s, its schedule %q is not one of %s",
+			repo.Slug, cron.Name, cron.Expr, strings.Join(cronExprs, ", "),
+		))
+		return
+	}
+
+	body := e.resource(
+		"drone_cron",
+		fmt.Sprintf("%s_%s", repo.Slug, cron.Name),
+		fmt.Sprintf("%s/%s", repo.Slug, cron.Name),
+	)
+
+	body.SetAttributeValue("repository", cty.StringVal(repo.Slug))
+	body.SetAttributeValue("name", cty.StringVal(cron.Name))
+	body.SetAttributeValue("expr", cty.StringVal(cron.Expr))
+	body.SetAttributeValue("event", cty.StringVal(cron.Event))
+	body.SetAttributeValue("branch", cty.StringVal(cron.Branch))
+	if cron.Target != "" {
+		body.SetAttributeValue("target", cty.StringVal(cron.Target))
+	}
+	body.SetAttributeValue("disabled", cty.BoolVal(cron.Disabled))
+}
+
+func (e *exporter) orgSecret(secret *drone.Secret) {
+	id := fmt.Sprintf("%s/%s", secret.Namespace, secret.Name)
+	body := e.resource("drone_orgsecret", id, id)
+
+	body.SetAttributeValue("namespace", cty.StringVal(secret.Namespace))
+	body.SetAttributeValue("name", cty.StringVal(secret.Name))
+	body.SetAttributeValue("value", cty.StringVal(exportSecretPlaceholder))
+	body.SetAttributeValue("allow_on_pull_request", cty.BoolVal(secret.PullRequest))
+	body.SetAttributeValue("allow_push_on_pull_request", cty.BoolVal(secret.PullRequestPush))
+
+	lifecycle := body.AppendNewBlock("lifecycle", nil).Body()
+	lifecycle.SetAttributeRaw("ignore_changes", hclwrite.TokensForTuple([]hclwrite.Tokens{
+		hclwrite.TokensForIdentifier("value"),
+	}))
+}
+
+func (e *exporter) template(template *namespacedTemplate) {
+	id := fmt.Sprintf("%s/%s", template.Namespace, template.Name)
+	body := e.resource("drone_template", id, id)
+
+	body.SetAttributeValue("namespace", cty.StringVal(template.Namespace))
+	body.SetAttributeValue("name", cty.StringVal(template.Name))
+	body.SetAttributeValue("data", cty.StringVal(template.Data))
+}
+
+func (e *exporter) user(user *drone.User) {
+	body := e.resource("drone_user", user.Login, user.Login)
+
+	body.SetAttributeValue("login", cty.StringVal(user.Login))
+	body.SetAttributeValue("active", cty.BoolVal(user.Active))
+	body.SetAttributeValue("admin", cty.BoolVal(user.Admin))
+	body.SetAttributeValue("machine", cty.BoolVal(user.Machine))
+}
+
+// resource appends a resource block and the import block adopting the
+// object with the given import ID, and returns the resource body.
+func (e *exporter) resource(kind, name, id string) *hclwrite.Body {
+	label := e.label(kind, name)
+	root := e.file.Body()
+
+	body := root.AppendNewBlock("resource", []string{kind, label}).Body()
+	root.AppendNewline()
+
+	imp := root.AppendNewBlock("import", nil).Body()
+	imp.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: kind},
+		hcl.TraverseAttr{Name: label},
+	})
+	imp.SetAttributeValue("id", cty.StringVal(id))
+	root.AppendNewline()
+
+	return body
+}
+
+// label turns name into a valid resource name which is unique among the
+// resources of the same kind.
+func (e *exporter) label(kind, name string) string {
+	base := exportLabelInvalidChars.ReplaceAllString(name, "_")
+	if base == "" || !isLabelStart(base[0]) {
+		base = "_" + base
+	}
+
+	if e.labels[kind] == nil {
+		e.labels[kind] = make(map[string]bool)
+	}
+
+	label := base
+	for i := 2; e.labels[kind][label]; i++ {
+		label = fmt.Sprintf("%s_%d", base, i)
+	}
+	e.labels[kind][label] = true
+
+	return label
+}
+
+func isCronExpr(expr string) bool {
+	for _, v := range cronExprs {
+		if v == expr {
+			return true
+		}
+	}
+	return false
+}
+
+func isLabelStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,146 @@
+package drone
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestExport(t *testing.T) {
+	responses := map[string]interface{}{
+		"/api/repos": []*drone.Repo{
+			{Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world", Active: true, Config: ".drone.yml", Timeout: 60, Visibility: "private"},
+			{Namespace: "octocat", Name: "inactive", Slug: "octocat/inactive"},
+		},
+		"/api/repos/octocat/hello-world/cron": []*drone.Cron{
+			{Name: "nightly", Expr: "@daily", Event: "push", Branch: "main"},
+			{Name: "weekdays", Expr: "0 0 8 * * 1-5", Event: "push", Branch: "main"},
+		},
+		"/api/secrets": []*drone.Secret{
+			{Namespace: "octocat", Name: "docker_password", PullRequest: true},
+		},
+		"/api/templates": []*namespacedTemplate{
+			{Namespace: "octocat", Name: "base.yaml", Data: "kind: pipeline\nname: ${name}\n"},
+		},
+		"/api/users": []*drone.User{
+			{Login: "octocat", Active: true, Admin: true},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		out, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		// Repositories are paged, so only the first page has results.
+		if page := r.URL.Query().Get("page"); page != "" && page != "1" {
+			out = []*drone.Repo{}
+		}
+		json.NewEncoder(w).Encode(out)
+	}))
+	defer server.Close()
+
+	e := newExporter(
+		drone.NewClient(server.URL, http.DefaultClient),
+		newAPIClient(server.URL, http.DefaultClient),
+	)
+
+	var buf bytes.Buffer
+	if err := e.export(&buf, repoSourceAll); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, diags := hclsyntax.ParseConfig(buf.Bytes(), "export.tf", hcl.InitialPos); diags.HasErrors() {
+		t.Fatalf("invalid configuration: %s\n%s", diags, buf.String())
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		`resource "drone_repo" "octocat_hello-world"`,
+		`to = drone_repo.octocat_hello-world`,
+		`id = "octocat/hello-world"`,
+		`resource "drone_cron" "octocat_hello-world_nightly"`,
+		`id = "octocat/hello-world/nightly"`,
+		`resource "drone_orgsecret" "octocat_docker_password"`,
+		`value                      = "REPLACE_ME"`,
+		`ignore_changes = [value]`,
+		`resource "drone_template" "octocat_base_yaml"`,
+		`$${name}`,
+		`resource "drone_user" "octocat"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q\n%s", want, out)
+		}
+	}
+
+	if strings.Contains(out, "octocat/inactive") {
+		t.Errorf("expected inactive repositories to be skipped\n%s", out)
+	}
+
+	if strings.Contains(out, "weekdays") {
+		t.Errorf("expected cron jobs with unsupported schedules to be skipped\n%s", out)
+	}
+	if len(e.skipped) != 1 || !strings.Contains(e.skipped[0], "octocat/hello-world/weekdays") {
+		t.Errorf("expected the weekdays cron job to be reported as skipped, got %v", e.skipped)
+	}
+}
+
+func TestExportUserSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/user/repos":
+			json.NewEncoder(w).Encode([]*drone.Repo{
+				{Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world", Active: true},
+			})
+		case "/api/repos/octocat/hello-world/cron":
+			json.NewEncoder(w).Encode([]*drone.Cron{})
+		default:
+			// Everything else is only listed for admins.
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	e := newExporter(
+		drone.NewClient(server.URL, http.DefaultClient),
+		newAPIClient(server.URL, http.DefaultClient),
+	)
+
+	var buf bytes.Buffer
+	if err := e.export(&buf, repoSourceList); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if out := buf.String(); !strings.Contains(out, `resource "drone_repo" "octocat_hello-world"`) {
+		t.Errorf("expected the repositories of the user to be exported\n%s", out)
+	}
+	if len(e.skipped) != 1 || !strings.Contains(e.skipped[0], "organization secrets, templates and users") {
+		t.Errorf("expected the admin only objects to be reported as skipped, got %v", e.skipped)
+	}
+}
+
+func TestExportLabels(t *testing.T) {
+	e := newExporter(nil, nil)
+
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{"octocat/hello-world", "octocat_hello-world"},
+		{"octocat/hello-world", "octocat_hello-world_2"},
+		{"octocat/hello.world", "octocat_hello_world"},
+		{"1password", "_1password"},
+	} {
+		if got := e.label("drone_repo", tc.name); got != tc.want {
+			t.Errorf("expected label %q for %q, got %q", tc.want, tc.name, got)
+		}
+	}
+}
@@ -2,14 +2,10 @@ package drone
 
 import (
 	"context"
-	"crypto/tls"
-	"net/http"
+	"fmt"
 
-	"github.com/drone/drone-go/drone"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/jackspirou/syscerts"
-	"golang.org/x/oauth2"
 )
 
 // Provider -
@@ -29,54 +25,97 @@ func Provider() *schema.Provider {
 				Description: "API Token for the drone server",
 				DefaultFunc: schema.EnvDefaultFunc("DRONE_TOKEN", nil),
 			},
+			"server_profile": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Additional named Drone servers which resources can select with their `server_profile` attribute",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name used to select this server from a resource",
+						},
+						"server": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "URL for the drone server",
+						},
+						"token": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Sensitive:   true,
+							Description: "API Token for the drone server",
+						},
+					},
+				},
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"drone_cron":      resourceCron(),
-			"drone_orgsecret": resourceOrgSecret(),
-			"drone_repo":      resourceRepo(),
-			"drone_secret":    resourceSecret(),
-			"drone_template":  resourceTemplate(),
-			"drone_user":      resourceUser(),
+			"drone_build_approval": resourceBuildApproval(),
+			"drone_build_purge":    resourceBuildPurge(),
+			"drone_build_restart":  resourceBuildRestart(),
+			"drone_cron":           resourceCron(),
+			"drone_orgsecret":      resourceOrgSecret(),
+			"drone_queue":          resourceQueue(),
+			"drone_repo":           resourceRepo(),
+			"drone_repos_config":   resourceReposConfig(),
+			"drone_secret":         resourceSecret(),
+			"drone_template":       resourceTemplate(),
+			"drone_user":           resourceUser(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"drone_repo":      dataSourceRepo(),
-			"drone_repos":     dataSourceRepos(),
-			"drone_template":  dataSourceTemplate(),
-			"drone_templates": dataSourceTemplates(),
-			"drone_user":      dataSourceUser(),
-			"drone_users":     dataSourceUsers(),
-			"drone_user_self": dataSourceUserSelf(),
+			"drone_build_logs":      dataSourceBuildLogs(),
+			"drone_crons":           dataSourceCrons(),
+			"drone_queue":           dataSourceQueue(),
+			"drone_repo":            dataSourceRepo(),
+			"drone_repos":           dataSourceRepos(),
+			"drone_template":        dataSourceTemplate(),
+			"drone_template_render": dataSourceTemplateRender(),
+			"drone_templates":       dataSourceTemplates(),
+			"drone_user":            dataSourceUser(),
+			"drone_users":           dataSourceUsers(),
+			"drone_user_self":       dataSourceUserSelf(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
 }
 
 func providerConfigure(ctx context.Context, data *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	config := new(oauth2.Config)
-
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
-	certs := syscerts.SystemRootsPool()
-	tlsConfig := &tls.Config{
-		RootCAs:            certs,
-		InsecureSkipVerify: false,
+	defaults := serverSettings{
+		Server: data.Get("server").(string),
+		Token:  data.Get("token").(string),
 	}
 
-	auther := config.Client(
-		oauth2.NoContext,
-		&oauth2.Token{AccessToken: data.Get("token").(string)},
-	)
+	profiles := make(map[string]serverSettings)
+	for _, v := range data.Get("server_profile").([]interface{}) {
+		profile := v.(map[string]interface{})
+		name := profile["name"].(string)
+
+		if _, ok := profiles[name]; ok {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Duplicate server_profile %q", name),
+			})
 
-	trans, _ := auther.Transport.(*oauth2.Transport)
-	trans.Base = &http.Transport{
-		TLSClientConfig: tlsConfig,
-		Proxy:           http.ProxyFromEnvironment,
+			return nil, diags
+		}
+
+		profiles[name] = serverSettings{
+			Server: profile["server"].(string),
+			Token:  profile["token"].(string),
+		}
 	}
 
-	server := data.Get("server").(string)
-	client := drone.NewClient(server, auther)
-	_, err := client.Self()
+	meta := newDroneMeta(defaults, profiles)
+
+	client, err := meta.client(ctx, "")
+	if err == nil {
+		_, err = client.Self()
+	}
 	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
@@ -85,5 +124,5 @@ func providerConfigure(ctx context.Context, data *schema.ResourceData) (interfac
 		})
 	}
 
-	return client, diags
+	return meta, diags
 }
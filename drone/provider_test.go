@@ -1,9 +1,11 @@
 package drone
 
 import (
+	"context"
 	"os"
 	"testing"
 
+	"github.com/drone/drone-go/drone"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -41,3 +43,10 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("DRONE_USER must be set for acceptance tests")
 	}
 }
+
+// testAccClient returns the client for the default server configured on the
+// acceptance test provider.
+func testAccClient() drone.Client {
+	client, _ := testAccProvider.Meta().(*droneMeta).client(context.Background(), "")
+	return client
+}
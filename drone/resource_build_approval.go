@@ -0,0 +1,211 @@
+package drone
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"terraform-provider-drone/drone/utils"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Build approval actions.
+const (
+	buildApprovalApprove = "approve"
+	buildApprovalDecline = "decline"
+)
+
+func resourceBuildApproval() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource for approving or declining a blocked stage of a Drone build. The decision cannot be undone, so destroying the resource only removes it from state",
+		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile("^[^/ ]+/[^/ ]+$"),
+					"Invalid repository (e.g. octocat/hello-world)",
+				),
+			},
+			"build_number": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"stage_number": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				ExactlyOneOf: []string{"stage_number", "stage_name"},
+				Description:  "Number of the blocked stage",
+			},
+			"stage_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"stage_number", "stage_name"},
+				Description:  "Name of the blocked stage",
+			},
+			"action": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					buildApprovalApprove,
+					buildApprovalDecline,
+				}, false),
+				Description: "Either `approve` or `decline`",
+			},
+			"approver": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Login of the user who approved or declined the stage",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Current status of the stage",
+			},
+		},
+
+		Timeouts: defaultResourceTimeouts(),
+
+		CreateContext: resourceBuildApprovalCreate,
+		ReadContext:   resourceBuildApprovalRead,
+		DeleteContext: resourceBuildApprovalDelete,
+	}
+}
+
+func resourceBuildApprovalCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	owner, repo, err := utils.ParseRepo(d.Get("repository").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	number := d.Get("build_number").(int)
+
+	build, err := client.Build(owner, repo, number)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stage, err := buildStage(build, d.Get("stage_number").(int), d.Get("stage_name").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if stage.Status != drone.StatusBlocked {
+		return diag.Errorf("Error: Stage %d (%s) of build %s/%s#%d is not blocked waiting for approval, its status is %s", stage.Number, stage.Name, owner, repo, number, stage.Status)
+	}
+
+	self, err := client.Self()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.Get("action").(string) == buildApprovalApprove {
+		err = client.Approve(owner, repo, number, stage.Number)
+	} else {
+		err = client.Decline(owner, repo, number, stage.Number)
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("approver", self.Login)
+	d.Set("stage_number", stage.Number)
+	d.SetId(fmt.Sprintf("%s/%s/%d/%d", owner, repo, number, stage.Number))
+
+	return resourceBuildApprovalRead(ctx, d, m)
+}
+
+func resourceBuildApprovalRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	owner, repo, err := utils.ParseRepo(d.Get("repository").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	number := d.Get("build_number").(int)
+
+	build, err := client.Build(owner, repo, number)
+	if isNotFound(err) {
+		// The build was purged, so the approval no longer exists.
+		d.SetId("")
+		return diags
+	}
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Failed to read Drone build: %s/%s#%d", owner, repo, number),
+			Detail:   err.Error(),
+		})
+
+		return diags
+	}
+
+	stage, err := buildStage(build, d.Get("stage_number").(int), "")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("stage_number", stage.Number)
+	d.Set("stage_name", stage.Name)
+	d.Set("status", stage.Status)
+
+	return diags
+}
+
+func resourceBuildApprovalDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	// An approval or decline cannot be undone.
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+
+	return diags
+}
+
+// buildStage returns the stage of build with the given number, or with the
+// given name when number is zero.
+func buildStage(build *drone.Build, number int, name string) (*drone.Stage, error) {
+	for _, stage := range build.Stages {
+		if number != 0 && stage.Number == number {
+			return stage, nil
+		}
+		if number == 0 && stage.Name == name {
+			return stage, nil
+		}
+	}
+
+	if number != 0 {
+		return nil, fmt.Errorf("Error: Build #%d has no stage %d", build.Number, number)
+	}
+
+	return nil, fmt.Errorf("Error: Build #%d has no stage named %q", build.Number, name)
+}
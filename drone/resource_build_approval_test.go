@@ -0,0 +1,53 @@
+package drone
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDroneBuildApprovalStageSelection(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+	resource "drone_build_approval" "approval" {
+		repository   = "octocat/hello-world"
+		build_number = 1
+		stage_number = 1
+		stage_name   = "deploy"
+		action       = "approve"
+	}
+	`,
+				ExpectError: regexp.MustCompile("only one of"),
+			},
+		},
+	})
+}
+
+func TestBuildStage(t *testing.T) {
+	build := &drone.Build{
+		Number: 42,
+		Stages: []*drone.Stage{
+			{Number: 1, Name: "test"},
+			{Number: 2, Name: "deploy"},
+		},
+	}
+
+	if stage, err := buildStage(build, 2, ""); err != nil || stage.Name != "deploy" {
+		t.Errorf("expected stage 2 to be deploy, got %v, %v", stage, err)
+	}
+	if stage, err := buildStage(build, 0, "test"); err != nil || stage.Number != 1 {
+		t.Errorf("expected stage test to be number 1, got %v, %v", stage, err)
+	}
+	if _, err := buildStage(build, 3, ""); err == nil {
+		t.Error("expected an error for a missing stage number")
+	}
+	if _, err := buildStage(build, 0, "release"); err == nil {
+		t.Error("expected an error for a missing stage name")
+	}
+}
@@ -0,0 +1,440 @@
+package drone
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"terraform-provider-drone/drone/utils"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceBuildPurge() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource for applying a build retention policy to a Drone repository, or to every repository matching a filter. Every plan checks the build history, and every apply purges the builds and logs falling outside the policy. Destroying the resource stops purging, purged builds cannot be restored",
+		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
+			"last_updated": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"repository": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile("^[^/ ]+/[^/ ]+$"),
+					"Invalid repository (e.g. octocat/hello-world)",
+				),
+				ConflictsWith: []string{"namespace", "name_regex"},
+				AtLeastOneOf:  []string{"repository", "namespace", "name_regex"},
+				Description:   "Apply the policy to this repository",
+			},
+			"source": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  repoSourceList,
+				ValidateFunc: validation.StringInSlice([]string{
+					repoSourceSync,
+					repoSourceList,
+					repoSourceAll,
+				}, false),
+				Description: "How repositories matching `namespace` and `name_regex` are listed: `sync` refreshes the user's repositories from the host system, `list` returns the user's repositories without syncing, and `all` returns every repository on the server (admin only)",
+			},
+			"namespace": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				AtLeastOneOf: []string{"repository", "namespace", "name_regex"},
+				Description:  "Apply the policy to active repositories in this namespace",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				AtLeastOneOf: []string{"repository", "namespace", "name_regex"},
+				Description:  "Apply the policy to active repositories whose name matches this regular expression",
+			},
+			"keep_builds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				AtLeastOneOf: []string{"keep_builds", "log_retention_days"},
+				Description:  "Number of most recent builds to keep, older builds are purged along with their logs",
+			},
+			"log_retention_days": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				AtLeastOneOf: []string{"keep_builds", "log_retention_days"},
+				Description:  "Purge the logs of builds created more than this many days ago, keeping the builds themselves",
+			},
+			"dry_run": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only report what would be purged in `pending_builds` and `pending_logs` during plan, without purging anything",
+			},
+			"pending_builds": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "Number of builds per repository which fall outside the policy",
+			},
+			"pending_logs": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "Number of builds per repository whose logs fall outside the policy",
+			},
+			"purged_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of builds and build logs purged by the last apply",
+			},
+			"logs_purged_through": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "Highest build number per repository whose logs have been purged",
+			},
+		},
+
+		Timeouts: defaultResourceTimeouts(),
+
+		CustomizeDiff: resourceBuildPurgeCustomizeDiff,
+
+		CreateContext: resourceBuildPurgeCreate,
+		ReadContext:   resourceBuildPurgeRead,
+		UpdateContext: resourceBuildPurgeUpdate,
+		DeleteContext: resourceBuildPurgeDelete,
+	}
+}
+
+func resourceBuildPurgeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := applyBuildPurges(ctx, d, m); diags.HasError() {
+		return diags
+	}
+
+	// The filters can change in place, so the ID does not depend on them.
+	d.SetId(resource.UniqueId())
+
+	return resourceBuildPurgeRead(ctx, d, m)
+}
+
+func resourceBuildPurgeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	purges, err := planBuildPurges(client, d, d.Get("logs_purged_through").(map[string]interface{}))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Failed to retrieve build history",
+			Detail:   err.Error(),
+		})
+
+		return diags
+	}
+
+	builds, logs := pendingBuildPurges(purges)
+
+	d.Set("pending_builds", builds)
+	d.Set("pending_logs", logs)
+
+	return diags
+}
+
+func resourceBuildPurgeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := applyBuildPurges(ctx, d, m); diags.HasError() {
+		return diags
+	}
+
+	d.Set("last_updated", time.Now().Format(time.RFC850))
+
+	return resourceBuildPurgeRead(ctx, d, m)
+}
+
+func resourceBuildPurgeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	// Purged builds cannot be restored, so destroying the policy only stops
+	// further purges.
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+
+	return diags
+}
+
+// resourceBuildPurgeCustomizeDiff checks the build history during plan. In
+// dry-run mode the builds and logs outside the policy are reported, otherwise
+// an update purging them is planned.
+func resourceBuildPurgeCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	client, err := m.(*droneMeta).client(ctx, d.Get("server_profile").(string))
+	if err != nil {
+		return err
+	}
+
+	purges, err := planBuildPurges(client, d, d.Get("logs_purged_through").(map[string]interface{}))
+	if err != nil {
+		return err
+	}
+
+	builds, logs := pendingBuildPurges(purges)
+
+	if d.Get("dry_run").(bool) {
+		if err := d.SetNew("pending_builds", builds); err != nil {
+			return err
+		}
+		return d.SetNew("pending_logs", logs)
+	}
+
+	if len(builds) == 0 && len(logs) == 0 {
+		return nil
+	}
+
+	for _, key := range []string{"pending_builds", "pending_logs", "purged_count", "logs_purged_through"} {
+		if err := d.SetNewComputed(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildPurge describes the builds and logs of a repository which fall
+// outside the retention policy.
+type buildPurge struct {
+	repo *drone.Repo
+
+	// before is the lowest build number which is kept, builds numbered below
+	// it are purged.
+	before int64
+	builds int
+
+	// logs holds the numbers of the builds whose logs are purged, newest
+	// first.
+	logs []int64
+}
+
+// planBuildPurges returns the purges for every repository the policy applies
+// to. through holds the highest build number per repository whose logs have
+// already been purged.
+func planBuildPurges(client drone.Client, d resourceGetter, through map[string]interface{}) ([]*buildPurge, error) {
+	var repos []*drone.Repo
+
+	if repository, ok := d.GetOk("repository"); ok {
+		owner, name, err := utils.ParseRepo(repository.(string))
+		if err != nil {
+			return nil, err
+		}
+
+		repo, err := client.Repo(owner, name)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, repo)
+	} else {
+		selected, err := selectRepos(client, d)
+		if err != nil {
+			return nil, err
+		}
+		repos = selected
+	}
+
+	keep := int64(d.Get("keep_builds").(int))
+
+	var cutoff int64
+	if days := d.Get("log_retention_days").(int); days > 0 {
+		cutoff = time.Now().AddDate(0, 0, -days).Unix()
+	}
+
+	purges := make([]*buildPurge, 0, len(repos))
+	for _, repo := range repos {
+		var purgedThrough int64
+		if v, ok := through[repo.Slug]; ok {
+			purgedThrough = int64(v.(int))
+		}
+
+		purge, err := planBuildPurge(client, repo, keep, cutoff, purgedThrough)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", repo.Slug, err)
+		}
+		purges = append(purges, purge)
+	}
+
+	return purges, nil
+}
+
+// planBuildPurge walks the build history of repo, newest build first. Builds
+// beyond the keep most recent are purged, and the logs of finished builds
+// created before cutoff are purged unless they were already purged.
+func planBuildPurge(client drone.Client, repo *drone.Repo, keep, cutoff, through int64) (*buildPurge, error) {
+	purge := &buildPurge{repo: repo}
+
+	// Build numbers can have gaps, for example when the build counter was
+	// raised, so the builds are counted rather than their numbers.
+	var seen int64
+
+	for page := 1; ; page++ {
+		builds, err := client.BuildList(repo.Namespace, repo.Name, drone.ListOptions{
+			Page: page,
+			Size: buildListPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(builds) == 0 {
+			return purge, nil
+		}
+
+		for _, build := range builds {
+			seen++
+			if keep > 0 && seen == keep {
+				purge.before = build.Number
+			}
+
+			if build.Number < purge.before {
+				purge.builds++
+				continue
+			}
+
+			if build.Number <= through {
+				if keep == 0 {
+					return purge, nil
+				}
+				continue
+			}
+
+			if cutoff > 0 && build.Finished != 0 && build.Created < cutoff {
+				purge.logs = append(purge.logs, build.Number)
+			}
+		}
+	}
+}
+
+// pendingBuildPurges returns the number of builds and build logs to purge per
+// repository.
+func pendingBuildPurges(purges []*buildPurge) (builds, logs map[string]int) {
+	builds = make(map[string]int)
+	logs = make(map[string]int)
+
+	for _, purge := range purges {
+		if purge.builds > 0 {
+			builds[purge.repo.Slug] = purge.builds
+		}
+		if len(purge.logs) > 0 {
+			logs[purge.repo.Slug] = len(purge.logs)
+		}
+	}
+
+	return
+}
+
+// applyBuildPurges purges the builds and logs outside the policy, unless in
+// dry-run mode, and records the outcome in purged_count and
+// logs_purged_through.
+func applyBuildPurges(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.Get("dry_run").(bool) {
+		d.Set("purged_count", 0)
+		return nil
+	}
+
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	through := d.Get("logs_purged_through").(map[string]interface{})
+
+	purges, err := planBuildPurges(client, d, through)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var diags diag.Diagnostics
+
+	purged := 0
+	for _, purge := range purges {
+		count, purgedThrough, err := applyBuildPurge(client, purge)
+		purged += count
+
+		if purgedThrough > 0 {
+			through[purge.repo.Slug] = int(purgedThrough)
+		}
+
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Failed to purge builds of Drone Repo: %s", purge.repo.Slug),
+				Detail:   err.Error(),
+			})
+		}
+	}
+
+	d.Set("purged_count", purged)
+	d.Set("logs_purged_through", through)
+
+	return diags
+}
+
+// applyBuildPurge purges the builds and logs of a single repository. It
+// returns the number of builds and build logs purged, and the highest build
+// number up to which every log in the purge was purged. Logs are purged
+// oldest first, so that after an error the logs which were not purged are
+// still above that number and are retried.
+func applyBuildPurge(client drone.Client, purge *buildPurge) (purged int, through int64, err error) {
+	namespace, name := purge.repo.Namespace, purge.repo.Name
+
+	if purge.builds > 0 {
+		if err = client.BuildPurge(namespace, name, int(purge.before)); err != nil {
+			return
+		}
+		purged += purge.builds
+	}
+
+	for i := len(purge.logs) - 1; i >= 0; i-- {
+		number := purge.logs[i]
+
+		var build *drone.Build
+
+		build, err = client.Build(namespace, name, int(number))
+		if err != nil {
+			return
+		}
+
+		for _, stage := range build.Stages {
+			for _, step := range stage.Steps {
+				// Skipped steps have no logs.
+				err = client.LogsPurge(namespace, name, int(number), stage.Number, step.Number)
+				if err != nil && !isNotFound(err) {
+					return
+				}
+				err = nil
+			}
+		}
+
+		purged++
+		through = number
+	}
+
+	return
+}
@@ -0,0 +1,155 @@
+package drone
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDroneBuildPurgeDryRun(t *testing.T) {
+	// testing requires a valid repository, currently I only have this working
+	// in my own local environment
+	scmAvail := os.Getenv("SCM_AVAIL")
+	if scmAvail == "" {
+		t.Skip("set SCM_AVAIL to run this test")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDroneBuildPurgeConfigDryRun(testDroneUser),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(
+						"drone_build_purge.policy",
+						"id",
+					),
+					resource.TestCheckResourceAttr(
+						"drone_build_purge.policy",
+						"purged_count",
+						"0",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDroneBuildPurgeConfigDryRun(namespace string) string {
+	return fmt.Sprintf(`
+	resource "drone_build_purge" "policy" {
+		namespace          = "%s"
+		keep_builds        = 1
+		log_retention_days = 1
+		dry_run            = true
+	}
+	`, namespace)
+}
+
+func TestPlanBuildPurge(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -30).Unix()
+	recent := time.Now().Unix()
+
+	// Builds are listed newest first.
+	builds := make([]*drone.Build, 0)
+	for number := int64(10); number > 0; number-- {
+		created := recent
+		if number <= 5 {
+			created = old
+		}
+		builds = append(builds, &drone.Build{Number: number, Created: created, Finished: created})
+	}
+
+	// Build numbers jump from 50 to 1000 after the build counter was raised.
+	gapped := make([]*drone.Build, 0)
+	for number := int64(1004); number > 999; number-- {
+		gapped = append(gapped, &drone.Build{Number: number, Created: recent, Finished: recent})
+	}
+	for number := int64(50); number > 45; number-- {
+		gapped = append(gapped, &drone.Build{Number: number, Created: old, Finished: old})
+	}
+
+	var history []*drone.Build
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if page := r.URL.Query().Get("page"); page != "1" {
+			json.NewEncoder(w).Encode([]*drone.Build{})
+			return
+		}
+		json.NewEncoder(w).Encode(history)
+	}))
+	defer server.Close()
+
+	client := drone.NewClient(server.URL, http.DefaultClient)
+	repo := &drone.Repo{Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world"}
+	cutoff := time.Now().AddDate(0, 0, -7).Unix()
+
+	for _, tc := range []struct {
+		history []*drone.Build
+		keep    int64
+		cutoff  int64
+		through int64
+		before  int64
+		builds  int
+		logs    []int64
+	}{
+		{history: builds, keep: 7, before: 4, builds: 3},
+		{history: builds, keep: 20},
+		{history: builds, cutoff: cutoff, logs: []int64{5, 4, 3, 2, 1}},
+		{history: builds, cutoff: cutoff, through: 3, logs: []int64{5, 4}},
+		{history: builds, keep: 7, cutoff: cutoff, through: 4, before: 4, builds: 3, logs: []int64{5}},
+		{history: gapped, keep: 7, before: 49, builds: 3},
+	} {
+		history = tc.history
+
+		purge, err := planBuildPurge(client, repo, tc.keep, tc.cutoff, tc.through)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if purge.before != tc.before || purge.builds != tc.builds || !reflect.DeepEqual(purge.logs, tc.logs) {
+			t.Errorf("keep %d, through %d: expected before %d, %d builds and logs %v, got before %d, %d builds and logs %v",
+				tc.keep, tc.through, tc.before, tc.builds, tc.logs, purge.before, purge.builds, purge.logs)
+		}
+	}
+}
+
+func TestApplyBuildPurgeOldestFirst(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/builds/4") {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(&drone.Build{
+			Stages: []*drone.Stage{{Number: 1, Steps: []*drone.Step{{Number: 1}}}},
+		})
+	}))
+	defer server.Close()
+
+	client := drone.NewClient(server.URL, http.DefaultClient)
+	repo := &drone.Repo{Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world"}
+
+	purged, through, err := applyBuildPurge(client, &buildPurge{repo: repo, logs: []int64{5, 4, 3, 2, 1}})
+	if err == nil {
+		t.Fatal("expected an error for build 4")
+	}
+
+	// Build 5 was not purged, so it must stay above through to be retried.
+	if purged != 3 || through != 3 {
+		t.Errorf("expected 3 logs purged through build 3, got %d through build %d", purged, through)
+	}
+}
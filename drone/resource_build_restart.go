@@ -0,0 +1,219 @@
+package drone
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"terraform-provider-drone/drone/utils"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceBuildRestart() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource for restarting a Drone build. The build is restarted again whenever any of the arguments, such as `triggers`, change. Destroying the resource only removes it from state",
+		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile("^[^/ ]+/[^/ ]+$"),
+					"Invalid repository (e.g. octocat/hello-world)",
+				),
+			},
+			"build_number": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Number of the build to restart",
+			},
+			"params": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Parameters passed to the restarted build",
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that restart the build when changed",
+			},
+			"cancel_running": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+				Description: "Cancel the pending and running builds on the branch of the build before restarting it",
+			},
+			"branch": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Branch of the restarted build",
+			},
+			"cancelled_builds": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "Numbers of the builds cancelled before restarting",
+			},
+			"restarted_build_number": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of the build created by the restart",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Current status of the build created by the restart",
+			},
+		},
+
+		Timeouts: defaultResourceTimeouts(),
+
+		CreateContext: resourceBuildRestartCreate,
+		ReadContext:   resourceBuildRestartRead,
+		DeleteContext: resourceBuildRestartDelete,
+	}
+}
+
+func resourceBuildRestartCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	owner, repo, err := utils.ParseRepo(d.Get("repository").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	number := d.Get("build_number").(int)
+
+	build, err := client.Build(owner, repo, number)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	cancelled := make([]int64, 0)
+	if d.Get("cancel_running").(bool) {
+		cancelled, err = cancelRunningBuilds(client, owner, repo, build.Target)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	params := make(map[string]string)
+	for k, v := range d.Get("params").(map[string]interface{}) {
+		params[k] = v.(string)
+	}
+
+	restarted, err := client.BuildRestart(owner, repo, number, params)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("cancelled_builds", cancelled)
+	d.Set("restarted_build_number", restarted.Number)
+	d.SetId(fmt.Sprintf("%s/%s/%d", owner, repo, restarted.Number))
+
+	return resourceBuildRestartRead(ctx, d, m)
+}
+
+func resourceBuildRestartRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	owner, repo, err := utils.ParseRepo(d.Get("repository").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	number := d.Get("restarted_build_number").(int)
+
+	build, err := client.Build(owner, repo, number)
+	if isNotFound(err) {
+		// The restarted build was purged. Keep the resource so that the
+		// build is not restarted again until the triggers change.
+		return diags
+	}
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Failed to read Drone build: %s/%s#%d", owner, repo, number),
+			Detail:   err.Error(),
+		})
+
+		return diags
+	}
+
+	d.Set("branch", build.Target)
+	d.Set("status", build.Status)
+
+	return diags
+}
+
+func resourceBuildRestartDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	// A restarted build cannot be undone.
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+
+	return diags
+}
+
+// cancelRunningBuilds cancels the pending and running builds on branch and
+// returns their numbers. Builds are listed newest first, so listing stops at
+// the first page without any unfinished build.
+func cancelRunningBuilds(client drone.Client, owner, repo, branch string) ([]int64, error) {
+	cancelled := make([]int64, 0)
+
+	for page := 1; ; page++ {
+		builds, err := client.BuildList(owner, repo, drone.ListOptions{
+			Page: page,
+			Size: buildListPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		unfinished := false
+		for _, build := range builds {
+			if build.Status != drone.StatusPending && build.Status != drone.StatusRunning {
+				continue
+			}
+			unfinished = true
+
+			if build.Target != branch {
+				continue
+			}
+
+			if err := client.BuildCancel(owner, repo, int(build.Number)); err != nil {
+				return nil, err
+			}
+			cancelled = append(cancelled, build.Number)
+		}
+
+		if !unfinished {
+			return cancelled, nil
+		}
+	}
+}
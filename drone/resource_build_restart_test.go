@@ -0,0 +1,58 @@
+package drone
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/drone/drone-go/drone"
+)
+
+func TestCancelRunningBuilds(t *testing.T) {
+	builds := []*drone.Build{
+		{Number: 6, Target: "main", Status: drone.StatusPending},
+		{Number: 5, Target: "feature", Status: drone.StatusRunning},
+		{Number: 4, Target: "main", Status: drone.StatusRunning},
+		{Number: 3, Target: "main", Status: drone.StatusPassing},
+	}
+
+	var mu sync.Mutex
+	cancelled := make([]string, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			mu.Lock()
+			cancelled = append(cancelled, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+			mu.Unlock()
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "1":
+			json.NewEncoder(w).Encode(builds)
+		case "2":
+			json.NewEncoder(w).Encode([]*drone.Build{{Number: 2, Target: "main", Status: drone.StatusPassing}})
+		default:
+			t.Errorf("unexpected request for page %s", r.URL.Query().Get("page"))
+			json.NewEncoder(w).Encode([]*drone.Build{})
+		}
+	}))
+	defer server.Close()
+
+	client := drone.NewClient(server.URL, http.DefaultClient)
+
+	numbers, err := cancelRunningBuilds(client, "octocat", "hello-world", "main")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if expected := []int64{6, 4}; !reflect.DeepEqual(numbers, expected) {
+		t.Errorf("expected cancelled builds %v, got %v", expected, numbers)
+	}
+	if expected := []string{"6", "4"}; !reflect.DeepEqual(cancelled, expected) {
+		t.Errorf("expected cancel requests for %v, got %v", expected, cancelled)
+	}
+}
@@ -18,6 +18,7 @@ func resourceCron() *schema.Resource {
 	return &schema.Resource{
 		Description: "Resource for creating a Drone cronjob",
 		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
 			"last_updated": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -33,49 +34,55 @@ func resourceCron() *schema.Resource {
 				),
 			},
 			"disabled": {
-				Type:     schema.TypeBool,
-				Optional: true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Stop scheduling builds without deleting the cron job",
 			},
 			"event": {
-				Type:     schema.TypeString,
-				Required: true,
-				ValidateFunc: validation.StringInSlice([]string{
-					drone.EventPush,
-				}, false),
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(cronEvents, false),
 			},
 			"branch": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "master",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Branch to build. Defaults to the default branch of the repository. A warning is shown for other branches which have never been built by Drone, since they may not exist",
 			},
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the cron job. A renamed cron job is created under its new name before the old one is deleted",
 			},
 			"target": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Deployment target, required for the `promote` and `rollback` events",
 			},
 			"expr": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "@monthly",
-				ForceNew: true,
-				ValidateFunc: validation.StringInSlice([]string{
-					"@hourly",
-					"@daily",
-					"@weekly",
-					"@monthly",
-					"@yearly",
-				}, false),
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "@monthly",
+				ValidateFunc: validation.StringInSlice(cronExprs, false),
+				Description:  "Schedule of the cron job, which is changed in place. Drone servers which cannot update schedules fail the update unless `recreate_on_schedule_change` is set",
+			},
+			"recreate_on_schedule_change": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Delete and create the cron job again when the Drone server cannot change its schedule in place. The cron job misses runs while it does not exist and its previous and next run times are reset",
 			},
 		},
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceCronImport,
 		},
 
+		Timeouts: defaultResourceTimeouts(),
+
+		CustomizeDiff: resourceCronCustomizeDiff,
+
 		CreateContext: resourceCronCreate,
 		ReadContext:   resourceCronRead,
 		UpdateContext: resourceCronUpdate,
@@ -83,29 +90,66 @@ func resourceCron() *schema.Resource {
 	}
 }
 
-func resourceCronCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+// cronExprs are the schedules supported for cron jobs.
+var cronExprs = []string{
+	"@hourly",
+	"@daily",
+	"@weekly",
+	"@monthly",
+	"@yearly",
+}
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+// cronEvents are the events Drone accepts for cron jobs.
+var cronEvents = []string{
+	drone.EventPush,
+	drone.EventPullRequest,
+	drone.EventTag,
+	drone.EventPromote,
+	drone.EventRollback,
+	"cron",
+	"custom",
+}
+
+func resourceCronCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	owner, repo, err := utils.ParseRepo(d.Get("repository").(string))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	cron, err := client.CronCreate(owner, repo, createCron(d))
+	repository, err := client.Repo(owner, repo)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId(fmt.Sprintf("%s/%s/%s", owner, repo, cron.Name))
+	// The branch could not be looked up during plan when the repository was
+	// not known yet.
+	if d.Get("branch").(string) == "" {
+		d.Set("branch", repository.Branch)
+	}
 
-	return diags
+	diags := checkCronBranch(client, repository, d.Get("branch").(string))
+	if diags.HasError() {
+		return diags
+	}
+
+	err = createCronJob(client, d, owner, repo)
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	return append(diags, resourceCronRead(ctx, d, m)...)
 }
 
 func resourceCronRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -116,10 +160,15 @@ func resourceCronRead(ctx context.Context, d *schema.ResourceData, m interface{}
 	}
 
 	cron, err := client.Cron(owner, repo, name)
+	if isNotFound(err) {
+		// The cron job was deleted outside of Terraform.
+		d.SetId("")
+		return diags
+	}
 	if err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
-			Summary:  fmt.Sprintf("Failed to read Drone Cron: %s/%s/%s not found", owner, repo, name),
+			Summary:  fmt.Sprintf("Failed to read Drone Cron: %s/%s/%s", owner, repo, name),
 			Detail:   err.Error(),
 		})
 
@@ -132,25 +181,74 @@ func resourceCronRead(ctx context.Context, d *schema.ResourceData, m interface{}
 }
 
 func resourceCronUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
-
-	owner, repo, name, err := utils.ParseId(d.Id(), "cron_name")
+	client, err := m.(*droneMeta).clientFor(ctx, d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	_, err = client.CronUpdate(owner, repo, name, updateCron(d))
+	owner, repo, name, err := utils.ParseId(d.Id(), "cron_name")
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	if d.HasChange("branch") {
+		repository, err := client.Repo(owner, repo)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		if diags = checkCronBranch(client, repository, d.Get("branch").(string)); diags.HasError() {
+			return diags
+		}
+	}
+
+	// Drone cannot rename a cron job, so a renamed cron job is created under
+	// its new name before the old one is deleted. The schedule is never
+	// missing in between.
+	if d.HasChange("name") {
+		err = createCronJob(client, d, owner, repo)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = client.CronDelete(owner, repo, name)
+		if err != nil {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Failed to delete renamed Drone Cron: %s/%s/%s", owner, repo, name),
+				Detail:   err.Error(),
+			}}
+		}
+	} else if d.HasChange("expr") {
+		api, err := m.(*droneMeta).apiClientFor(ctx, d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		err = updateCronSchedule(client, api, d, owner, repo, name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		_, err = client.CronUpdate(owner, repo, name, updateCron(d))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	d.Set("last_updated", time.Now().Format(time.RFC850))
 
-	return resourceCronRead(ctx, d, m)
+	return append(diags, resourceCronRead(ctx, d, m)...)
 }
 
 func resourceCronDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -172,6 +270,146 @@ func resourceCronDelete(ctx context.Context, d *schema.ResourceData, m interface
 	return diags
 }
 
+// resourceCronCustomizeDiff defaults the branch to the default branch of the
+// repository and checks during plan that the target is set when the event
+// needs one.
+func resourceCronCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	event := d.Get("event").(string)
+	if (event == drone.EventPromote || event == drone.EventRollback) && d.NewValueKnown("target") && d.Get("target").(string) == "" {
+		return fmt.Errorf("Error: target is required for the %s event", event)
+	}
+
+	// The raw config is not sent by every caller, in which case any branch
+	// in the plan is taken to be configured.
+	var configured bool
+	if config := d.GetRawConfig(); config.IsNull() {
+		_, configured = d.GetOk("branch")
+	} else {
+		configured = !config.GetAttr("branch").IsNull()
+	}
+
+	// A configured branch is checked by Create and Update.
+	if configured {
+		return nil
+	}
+
+	if !d.NewValueKnown("repository") {
+		// Looked up by Create once the repository is known.
+		return d.SetNewComputed("branch")
+	}
+
+	owner, repo, err := utils.ParseRepo(d.Get("repository").(string))
+	if err != nil {
+		return err
+	}
+
+	client, err := m.(*droneMeta).client(ctx, d.Get("server_profile").(string))
+	if err != nil {
+		return err
+	}
+
+	repository, err := client.Repo(owner, repo)
+	if isNotFound(err) && d.Id() == "" {
+		// The repository may be activated in the same apply, so it is
+		// looked up by Create instead.
+		return d.SetNewComputed("branch")
+	}
+	if err != nil {
+		return err
+	}
+
+	if d.Get("branch").(string) == repository.Branch {
+		return nil
+	}
+
+	return d.SetNew("branch", repository.Branch)
+}
+
+// checkCronBranch warns when branch has never been built by Drone. Drone
+// cannot list the branches of a repository, so such a branch may not exist,
+// but it may also just have been pushed.
+func checkCronBranch(client drone.Client, repository *drone.Repo, branch string) diag.Diagnostics {
+	if branch == repository.Branch {
+		return nil
+	}
+
+	_, err := client.BuildLast(repository.Namespace, repository.Name, branch)
+	if isNotFound(err) {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Branch %s of %s has never been built by Drone", branch, repository.Slug),
+			Detail:   fmt.Sprintf("Drone cannot list the branches of a repository, so the branch could not be checked. The cron job does not start builds if the branch does not exist. The default branch is %s.", repository.Branch),
+		}}
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// updateCronSchedule updates the cron job in place, including its schedule.
+// Older Drone servers ignore the schedule in an update. The cron job is then
+// deleted and created again with the new schedule when
+// recreate_on_schedule_change is set, and the update fails otherwise.
+func updateCronSchedule(client drone.Client, api *apiClient, d *schema.ResourceData, owner, repo, name string) error {
+	expr := d.Get("expr").(string)
+
+	cron, err := api.CronUpdate(owner, repo, name, &cronPatch{
+		CronPatch: *updateCron(d),
+		Expr:      &expr,
+	})
+	if err != nil {
+		return err
+	}
+
+	if cron.Expr == expr {
+		return nil
+	}
+
+	if !d.Get("recreate_on_schedule_change").(bool) {
+		// Keep the schedule on the server in state, the other changes were
+		// applied.
+		d.Set("expr", cron.Expr)
+
+		return fmt.Errorf("Error: The Drone server cannot change the schedule of cron job %s/%s/%s from %s to %s in place. Set recreate_on_schedule_change to delete and create the cron job again", owner, repo, name, cron.Expr, expr)
+	}
+
+	err = client.CronDelete(owner, repo, name)
+	if err != nil {
+		return err
+	}
+
+	err = createCronJob(client, d, owner, repo)
+	if err != nil {
+		// The cron job no longer exists.
+		d.SetId("")
+		return err
+	}
+
+	return nil
+}
+
+// createCronJob creates the cron job configured by d and sets the ID.
+func createCronJob(client drone.Client, d *schema.ResourceData, owner, repo string) error {
+	cron, err := client.CronCreate(owner, repo, createCron(d))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", owner, repo, cron.Name))
+
+	// Drone ignores the disabled flag when creating a cron job.
+	if cron.Disabled != d.Get("disabled").(bool) {
+		_, err = client.CronUpdate(owner, repo, cron.Name, updateCron(d))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func createCron(d *schema.ResourceData) (repository *drone.Cron) {
 	return &drone.Cron{
 		Disabled: d.Get("disabled").(bool),
@@ -202,7 +440,28 @@ func readCron(d *schema.ResourceData, namespace string, repo string, cron *drone
 	d.Set("repository", fmt.Sprintf("%s/%s", namespace, repo))
 	d.Set("branch", cron.Branch)
 	d.Set("disabled", cron.Disabled)
+	d.Set("event", cron.Event)
 	d.Set("expr", cron.Expr)
 	d.Set("name", cron.Name)
 	d.Set("target", cron.Target)
 }
+
+// resourceCronImport validates a [profile:]owner/repo/cron_name import ID and
+// sets the attributes identifying the cron job.
+func resourceCronImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if err := importProfile(d, m); err != nil {
+		return nil, err
+	}
+
+	owner, repo, name, err := utils.ParseId(d.Id(), "cron_name")
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", owner, repo, name))
+	d.Set("repository", fmt.Sprintf("%s/%s", owner, repo))
+	d.Set("name", name)
+	d.Set("recreate_on_schedule_change", false)
+
+	return []*schema.ResourceData{d}, nil
+}
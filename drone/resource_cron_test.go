@@ -1,15 +1,23 @@
 package drone
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
+	"regexp"
 	"testing"
 
 	"terraform-provider-drone/drone/utils"
 
 	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -47,14 +55,215 @@ func TestAccDroneCronBasic(t *testing.T) {
 						"repository",
 						fmt.Sprintf("%s/repository-1", testDroneUser),
 					),
+					resource.TestCheckResourceAttrSet(
+						"drone_cron.cron",
+						"branch",
+					),
+				),
+			},
+			{
+				Config: testAccCheckDroneCronConfigEvent(
+					testDroneUser,
+					"repository-1",
+					rName,
+					"promote",
 				),
+				ExpectError: regexp.MustCompile("target is required for the promote event"),
 			},
 		},
 	})
 }
 
+func TestAccDroneCronRename(t *testing.T) {
+	// testing cronjobs requires a valid repository, currently I only have this working
+	// in my own local environment
+	scmAvail := os.Getenv("SCM_AVAIL")
+	if scmAvail == "" {
+		t.Skip("set SCM_AVAIL to run this test")
+	}
+
+	// generate a random name to avoid collisions from multiple concurrent tests.
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	id := fmt.Sprintf("%s/repository-1/%s", testDroneUser, rName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDroneCronDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDroneCronConfigSchedule(
+					testDroneUser,
+					"repository-1",
+					rName,
+					"@monthly",
+				),
+				Check: resource.TestCheckResourceAttr("drone_cron.cron", "id", id),
+			},
+			{
+				Config: testAccCheckDroneCronConfigSchedule(
+					testDroneUser,
+					"repository-1",
+					rName,
+					"@daily",
+				),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("drone_cron.cron", "id", id),
+					resource.TestCheckResourceAttr("drone_cron.cron", "expr", "@daily"),
+				),
+			},
+			{
+				Config: testAccCheckDroneCronConfigSchedule(
+					testDroneUser,
+					"repository-1",
+					rName+"-renamed",
+					"@daily",
+				),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("drone_cron.cron", "id", id+"-renamed"),
+					testAccCheckDroneCronMissing(testDroneUser, "repository-1", rName),
+				),
+			},
+		},
+	})
+}
+
+func TestCheckCronBranch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("branch") != "release" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(&drone.Build{Number: 1, Target: "release"})
+	}))
+	defer server.Close()
+
+	client := drone.NewClient(server.URL, http.DefaultClient)
+	repository := &drone.Repo{Namespace: "octocat", Name: "hello-world", Slug: "octocat/hello-world", Branch: "main"}
+
+	for _, tc := range []struct {
+		branch  string
+		warning bool
+	}{
+		{branch: "main"},
+		{branch: "release"},
+		{branch: "feature", warning: true},
+	} {
+		diags := checkCronBranch(client, repository, tc.branch)
+		if diags.HasError() {
+			t.Fatalf("%s: expected no error, got %v", tc.branch, diags)
+		}
+
+		warning := len(diags) == 1 && diags[0].Severity == diag.Warning
+		if warning != tc.warning || (!tc.warning && len(diags) > 0) {
+			t.Errorf("%s: expected warning %t, got %v", tc.branch, tc.warning, diags)
+		}
+	}
+}
+
+func TestUpdateCronSchedule(t *testing.T) {
+	for _, tc := range []struct {
+		supported  bool
+		recreate   bool
+		failCreate bool
+		requests   []string
+		err        bool
+		expr       string
+		id         string
+	}{
+		{supported: true, requests: []string{http.MethodPatch}, expr: "@daily", id: "octocat/hello-world/nightly"},
+		{requests: []string{http.MethodPatch}, err: true, expr: "@monthly", id: "octocat/hello-world/nightly"},
+		{recreate: true, requests: []string{http.MethodPatch, http.MethodDelete, http.MethodPost}, expr: "@daily", id: "octocat/hello-world/nightly"},
+		{recreate: true, failCreate: true, requests: []string{http.MethodPatch, http.MethodDelete, http.MethodPost}, err: true, expr: "@daily"},
+	} {
+		requests := make([]string, 0)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method)
+
+			if r.Method == http.MethodPost && tc.failCreate {
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			cron := &drone.Cron{Name: "nightly", Expr: "@monthly"}
+			if r.Method == http.MethodPost || (r.Method == http.MethodPatch && tc.supported) {
+				cron.Expr = "@daily"
+			}
+			json.NewEncoder(w).Encode(cron)
+		}))
+
+		d := schema.TestResourceDataRaw(t, resourceCron().Schema, map[string]interface{}{
+			"repository":                  "octocat/hello-world",
+			"name":                        "nightly",
+			"event":                       "push",
+			"branch":                      "main",
+			"expr":                        "@daily",
+			"recreate_on_schedule_change": tc.recreate,
+		})
+		d.SetId("octocat/hello-world/nightly")
+		client := drone.NewClient(server.URL, http.DefaultClient)
+		api := newAPIClient(server.URL, http.DefaultClient)
+
+		err := updateCronSchedule(client, api, d, "octocat", "hello-world", "nightly")
+		server.Close()
+
+		if (err != nil) != tc.err {
+			t.Errorf("supported %t, recreate %t: expected error %t, got %v", tc.supported, tc.recreate, tc.err, err)
+		}
+		if !reflect.DeepEqual(requests, tc.requests) {
+			t.Errorf("supported %t, recreate %t: expected requests %v, got %v", tc.supported, tc.recreate, tc.requests, requests)
+		}
+		if expr := d.Get("expr").(string); expr != tc.expr || d.Id() != tc.id {
+			t.Errorf("supported %t, recreate %t: expected expr %s and ID %q, got %s and %q", tc.supported, tc.recreate, tc.expr, tc.id, expr, d.Id())
+		}
+	}
+}
+
+func TestResourceCronCustomizeDiffBranch(t *testing.T) {
+	for _, tc := range []struct {
+		branch    string
+		requested bool
+		computed  bool
+	}{
+		{branch: "release"},
+		{requested: true, computed: true},
+	} {
+		requested := false
+
+		// The repository is not in the Drone database until it is activated.
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested = true
+			http.NotFound(w, r)
+		}))
+
+		config := map[string]interface{}{
+			"repository": "octocat/hello-world",
+			"name":       "nightly",
+			"event":      "push",
+		}
+		if tc.branch != "" {
+			config["branch"] = tc.branch
+		}
+		meta := newDroneMeta(serverSettings{Server: server.URL, Token: "token"}, nil)
+
+		diff, err := resourceCron().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), meta)
+		server.Close()
+		if err != nil {
+			t.Fatalf("branch %q: err: %s", tc.branch, err)
+		}
+
+		if requested != tc.requested {
+			t.Errorf("branch %q: expected repository requested %t, got %t", tc.branch, tc.requested, requested)
+		}
+		if computed := diff.Attributes["branch"].NewComputed; computed != tc.computed {
+			t.Errorf("branch %q: expected branch computed %t, got %t", tc.branch, tc.computed, computed)
+		}
+	}
+}
+
 func testAccCheckDroneCronDestroy(s *terraform.State) error {
-	c := testAccProvider.Meta().(drone.Client)
+	c := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "drone_cron" {
@@ -93,6 +302,56 @@ func testAccCheckDroneCronConfigBasic(user, repo, name string) string {
 	)
 }
 
+func testAccCheckDroneCronConfigEvent(user, repo, name, event string) string {
+	return fmt.Sprintf(`
+	resource "drone_repo" "repo" {
+		repository = "%s/%s"
+	}
+
+	resource "drone_cron" "cron" {
+		repository = drone_repo.repo.repository
+		name       = "%s"
+		expr       = "@monthly"
+		event      = "%s"
+	}
+	`,
+		user,
+		repo,
+		name,
+		event,
+	)
+}
+
+func testAccCheckDroneCronConfigSchedule(user, repo, name, expr string) string {
+	return fmt.Sprintf(`
+	resource "drone_repo" "repo" {
+		repository = "%s/%s"
+	}
+
+	resource "drone_cron" "cron" {
+		repository = drone_repo.repo.repository
+		name       = "%s"
+		expr       = "%s"
+		event      = "push"
+	}
+	`,
+		user,
+		repo,
+		name,
+		expr,
+	)
+}
+
+func testAccCheckDroneCronMissing(owner, repo, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if _, err := testAccClient().Cron(owner, repo, name); err == nil {
+			return fmt.Errorf("Cron (%s/%s/%s) still exists.", owner, repo, name)
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckDroneCronExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
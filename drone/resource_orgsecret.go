@@ -15,6 +15,7 @@ func resourceOrgSecret() *schema.Resource {
 	return &schema.Resource{
 		Description: "Resource for creating a Drone organization secret",
 		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
 			"last_updated": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -31,11 +32,12 @@ func resourceOrgSecret() *schema.Resource {
 				ForceNew: true,
 			},
 			"value": {
-				Type:      schema.TypeString,
-				Required:  true,
-				Sensitive: true,
-				ForceNew:  false,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"value", "docker_config"},
 			},
+			"docker_config": dockerConfigSchema(),
 			"allow_on_pull_request": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -49,9 +51,13 @@ func resourceOrgSecret() *schema.Resource {
 		},
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceOrgSecretImport,
 		},
 
+		Timeouts: defaultResourceTimeouts(),
+
+		CustomizeDiff: secretCustomizeDiff,
+
 		CreateContext: resourceOrgSecretCreate,
 		ReadContext:   resourceOrgSecretRead,
 		UpdateContext: resourceOrgSecretUpdate,
@@ -60,7 +66,10 @@ func resourceOrgSecret() *schema.Resource {
 }
 
 func resourceOrgSecretCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -78,7 +87,10 @@ func resourceOrgSecretCreate(ctx context.Context, d *schema.ResourceData, m inte
 }
 
 func resourceOrgSecretRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -99,20 +111,23 @@ func resourceOrgSecretRead(ctx context.Context, d *schema.ResourceData, m interf
 		return diags
 	}
 
-	readOrgSecret(d, secret)
+	readOrgSecret(d, namespace, secret)
 
 	return diags
 }
 
 func resourceOrgSecretUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	namespace, _, err := utils.ParseOrgId(d.Id(), "secret_name")
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	client.OrgSecretUpdate(namespace, createOrgSecret(d))
+	_, err = client.OrgSecretUpdate(namespace, createOrgSecret(d))
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -123,12 +138,18 @@ func resourceOrgSecretUpdate(ctx context.Context, d *schema.ResourceData, m inte
 }
 
 func resourceOrgSecretDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
 	namespace, name, err := utils.ParseOrgId(d.Id(), "secret_name")
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	err = client.OrgSecretDelete(namespace, name)
 	if err != nil {
@@ -145,14 +166,34 @@ func resourceOrgSecretDelete(ctx context.Context, d *schema.ResourceData, m inte
 func createOrgSecret(data *schema.ResourceData) (secret *drone.Secret) {
 	return &drone.Secret{
 		Name:            data.Get("name").(string),
-		Data:            data.Get("value").(string),
+		Data:            secretValue(data),
 		PullRequest:     data.Get("allow_on_pull_request").(bool),
 		PullRequestPush: data.Get("allow_push_on_pull_request").(bool),
 	}
 }
 
-func readOrgSecret(data *schema.ResourceData, secret *drone.Secret) {
+func readOrgSecret(data *schema.ResourceData, namespace string, secret *drone.Secret) {
+	data.Set("namespace", namespace)
 	data.Set("name", secret.Name)
 	data.Set("allow_on_pull_request", secret.PullRequest)
 	data.Set("allow_push_on_pull_request", secret.PullRequestPush)
 }
+
+// resourceOrgSecretImport validates a [profile:]namespace/secret_name import
+// ID and sets the attributes identifying the secret.
+func resourceOrgSecretImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if err := importProfile(d, m); err != nil {
+		return nil, err
+	}
+
+	namespace, name, err := utils.ParseOrgId(d.Id(), "secret_name")
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", namespace, name))
+	d.Set("namespace", namespace)
+	d.Set("name", name)
+
+	return []*schema.ResourceData{d}, nil
+}
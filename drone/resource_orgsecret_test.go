@@ -2,9 +2,9 @@ package drone
 
 import (
 	"fmt"
+	"os"
 	"testing"
 
-	"github.com/drone/drone-go/drone"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -44,12 +44,95 @@ func TestAccDroneOrgsecretBasic(t *testing.T) {
 					),
 				),
 			},
+			{
+				ResourceName:            "drone_orgsecret.secret",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"value"},
+			},
+		},
+	})
+}
+
+func TestAccDroneOrgsecretDockerConfig(t *testing.T) {
+	// generate a random name to avoid collisions from multiple concurrent tests.
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDroneOrgsecretDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDroneOrgsecretConfigDockerConfig(
+					"test",
+					rName,
+					"thisissecret",
+				),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDroneOrgsecretExists("drone_orgsecret.secret"),
+					resource.TestCheckResourceAttr(
+						"drone_orgsecret.secret",
+						"docker_config.0.registry",
+						"ghcr.io",
+					),
+				),
+			},
+			{
+				Config: testAccCheckDroneOrgsecretConfigDockerConfig(
+					"test",
+					rName,
+					"thisisanothersecret",
+				),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(
+						"drone_orgsecret.secret",
+						"last_updated",
+					),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDroneOrgsecretImportProfile(t *testing.T) {
+	// generate a random name to avoid collisions from multiple concurrent tests.
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDroneOrgsecretDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDroneOrgsecretConfigProfile(
+					"test",
+					rName,
+					"thisissecret",
+				),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDroneOrgsecretExists("drone_orgsecret.secret"),
+					resource.TestCheckResourceAttr(
+						"drone_orgsecret.secret",
+						"server_profile",
+						"secondary",
+					),
+				),
+			},
+			{
+				Config:                  testAccCheckDroneOrgsecretConfigProfile("test", rName, "thisissecret"),
+				ResourceName:            "drone_orgsecret.secret",
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("secondary:test/%s", rName),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"value"},
+			},
 		},
 	})
 }
 
 func testAccCheckDroneOrgsecretDestroy(s *terraform.State) error {
-	c := testAccProvider.Meta().(drone.Client)
+	c := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "drone_orgsecret" {
@@ -82,6 +165,52 @@ func testAccCheckDroneOrgsecretConfigBasic(namespace, name, value string) string
 	)
 }
 
+func testAccCheckDroneOrgsecretConfigDockerConfig(namespace, name, password string) string {
+	return fmt.Sprintf(`
+	resource "drone_orgsecret" "secret" {
+		namespace = "%s"
+		name      = "%s"
+
+		docker_config {
+			registry = "ghcr.io"
+			username = "octocat"
+			password = "%s"
+		}
+	}
+	`,
+		namespace,
+		name,
+		password,
+	)
+}
+
+// testAccCheckDroneOrgsecretConfigProfile declares a server_profile for the
+// acceptance test server and creates the secret through it.
+func testAccCheckDroneOrgsecretConfigProfile(namespace, name, value string) string {
+	return fmt.Sprintf(`
+	provider "drone" {
+		server_profile {
+			name   = "secondary"
+			server = "%s"
+			token  = "%s"
+		}
+	}
+
+	resource "drone_orgsecret" "secret" {
+		server_profile = "secondary"
+		namespace      = "%s"
+		name           = "%s"
+		value          = "%s"
+	}
+	`,
+		os.Getenv("DRONE_SERVER"),
+		os.Getenv("DRONE_TOKEN"),
+		namespace,
+		name,
+		value,
+	)
+}
+
 func testAccCheckDroneOrgsecretExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
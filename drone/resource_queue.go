@@ -0,0 +1,120 @@
+package drone
+
+import (
+	"context"
+	"time"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceQueue() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource for pausing and resuming the Drone build queue (admin only). Drone does not report whether the queue is paused, so the configured state is applied on every change. Destroying the resource resumes the queue",
+		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
+			"last_updated": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"paused": {
+				Type:        schema.TypeBool,
+				Required:    true,
+				Description: "Whether the queue is paused. Builds are still accepted while the queue is paused, but no pending stage is scheduled",
+			},
+		},
+
+		Timeouts: defaultResourceTimeouts(),
+
+		CreateContext: resourceQueueCreate,
+		ReadContext:   resourceQueueRead,
+		UpdateContext: resourceQueueUpdate,
+		DeleteContext: resourceQueueDelete,
+	}
+}
+
+func resourceQueueCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setQueuePaused(client, d.Get("paused").(bool)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("queue")
+
+	return resourceQueueRead(ctx, d, m)
+}
+
+func resourceQueueRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	// Listing the queue checks that the token may manage it. The paused
+	// state itself cannot be read, so the value in state is kept.
+	_, err = client.Queue()
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Failed to read Drone queue",
+			Detail:   err.Error(),
+		})
+
+		return diags
+	}
+
+	return diags
+}
+
+func resourceQueueUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := setQueuePaused(client, d.Get("paused").(bool)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("last_updated", time.Now().Format(time.RFC850))
+
+	return resourceQueueRead(ctx, d, m)
+}
+
+func resourceQueueDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	err = client.QueueResume()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+
+	return diags
+}
+
+func setQueuePaused(client drone.Client, paused bool) error {
+	if paused {
+		return client.QueuePause()
+	}
+
+	return client.QueueResume()
+}
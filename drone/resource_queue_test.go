@@ -0,0 +1,45 @@
+package drone
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDroneQueueBasic(t *testing.T) {
+	// Drone does not report whether the queue is paused, so there is nothing
+	// to check after destroy beyond the resume call succeeding.
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDroneQueueConfigBasic(true),
+				Check: resource.TestCheckResourceAttr(
+					"drone_queue.queue",
+					"paused",
+					"true",
+				),
+			},
+			{
+				Config: testAccCheckDroneQueueConfigBasic(false),
+				Check: resource.TestCheckResourceAttr(
+					"drone_queue.queue",
+					"paused",
+					"false",
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDroneQueueConfigBasic(paused bool) string {
+	return fmt.Sprintf(`
+	resource "drone_queue" "queue" {
+		paused = %t
+	}
+	`,
+		paused,
+	)
+}
@@ -3,7 +3,9 @@ package drone
 import (
 	"context"
 	"fmt"
+	"path"
 	"regexp"
+	"strings"
 	"time"
 
 	"terraform-provider-drone/drone/utils"
@@ -14,26 +16,67 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+const (
+	// repoTimeoutMin and repoTimeoutMax bound the build timeout, in minutes,
+	// which can be set without an admin token.
+	repoTimeoutMin = 1
+	repoTimeoutMax = 1440
+)
+
+// repoVisibilities are the repository visibilities supported by Drone.
+var repoVisibilities = []string{
+	"public",
+	"private",
+	"internal",
+}
+
+// repoSettings reads the value of each repository setting shared by
+// drone_repo and drone_repos_config, keyed by attribute name.
+var repoSettings = map[string]func(*drone.Repo) interface{}{
+	"cancel_pulls":   func(r *drone.Repo) interface{} { return r.CancelPulls },
+	"cancel_push":    func(r *drone.Repo) interface{} { return r.CancelPush },
+	"cancel_running": func(r *drone.Repo) interface{} { return r.CancelRunning },
+	"configuration":  func(r *drone.Repo) interface{} { return r.Config },
+	"ignore_forks":   func(r *drone.Repo) interface{} { return r.IgnoreForks },
+	"ignore_pulls":   func(r *drone.Repo) interface{} { return r.IgnorePulls },
+	"protected":      func(r *drone.Repo) interface{} { return r.Protected },
+	"throttle":       func(r *drone.Repo) interface{} { return int(r.Throttle) },
+	"timeout":        func(r *drone.Repo) interface{} { return int(r.Timeout) },
+	"trusted":        func(r *drone.Repo) interface{} { return r.Trusted },
+	"visibility":     func(r *drone.Repo) interface{} { return r.Visibility },
+}
+
 func resourceRepo() *schema.Resource {
 	return &schema.Resource{
-		Description: "Resource for managing a Drone repository",
+		Description: "Resource for managing a Drone repository. Settings which are not set are left as they are on the server",
 		Schema: map[string]*schema.Schema{
+			"build_counter": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Minimum value for the build number counter, for example to continue build numbering after a migration. The counter is only raised, never lowered",
+			},
 			"cancel_pulls": {
 				Type:     schema.TypeBool,
 				Optional: true,
+				Computed: true,
 			},
 			"cancel_push": {
 				Type:     schema.TypeBool,
 				Optional: true,
+				Computed: true,
 			},
 			"cancel_running": {
 				Type:     schema.TypeBool,
 				Optional: true,
+				Computed: true,
 			},
 			"configuration": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  ".drone.yml",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateRepoConfiguration,
+				Description:  "Path to the pipeline configuration file relative to the repository root, or an http(s) URL handled by a configuration extension",
 			},
 			"last_updated": {
 				Type:     schema.TypeString,
@@ -43,14 +86,17 @@ func resourceRepo() *schema.Resource {
 			"ignore_forks": {
 				Type:     schema.TypeBool,
 				Optional: true,
+				Computed: true,
 			},
 			"ignore_pulls": {
 				Type:     schema.TypeBool,
 				Optional: true,
+				Computed: true,
 			},
 			"protected": {
 				Type:     schema.TypeBool,
 				Optional: true,
+				Computed: true,
 			},
 			"repository": {
 				Type:     schema.TypeString,
@@ -61,26 +107,43 @@ func resourceRepo() *schema.Resource {
 					"Invalid repository (e.g. octocat/hello-world)",
 				),
 			},
+			"server_profile": serverProfileSchema(),
+			"throttle": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of concurrent builds for the repository, 0 means unlimited",
+			},
 			"timeout": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  60,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(repoTimeoutMin),
+				Description:  fmt.Sprintf("Build timeout in minutes. Timeouts above %d minutes require an admin token", repoTimeoutMax),
 			},
 			"trusted": {
-				Type:     schema.TypeBool,
-				Optional: true,
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether builds may use privileged features. Enabling this requires an admin token",
 			},
 			"visibility": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "private",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(repoVisibilities, false),
 			},
 		},
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceRepoImport,
 		},
 
+		Timeouts: defaultResourceTimeouts(),
+
+		CustomizeDiff: resourceRepoCustomizeDiff,
+
 		CreateContext: resourceRepoCreate,
 		ReadContext:   resourceRepoRead,
 		UpdateContext: resourceRepoUpdate,
@@ -89,7 +152,10 @@ func resourceRepo() *schema.Resource {
 }
 
 func resourceRepoCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -104,12 +170,12 @@ func resourceRepoCreate(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.FromErr(err)
 	}
 
-	_, err = client.Repo(owner, repo)
+	current, err := client.Repo(owner, repo)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	resp, err := client.RepoUpdate(owner, repo, createRepo(d))
+	resp, err := client.RepoUpdate(owner, repo, raiseCounterOnly(createRepo(d), current))
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -124,11 +190,14 @@ func resourceRepoCreate(ctx context.Context, d *schema.ResourceData, m interface
 
 	d.SetId(fmt.Sprintf("%s/%s", owner, repo))
 
-	return diags
+	return append(diags, resourceRepoRead(ctx, d, m)...)
 }
 
 func resourceRepoRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -155,11 +224,24 @@ func resourceRepoRead(ctx context.Context, d *schema.ResourceData, m interface{}
 }
 
 func resourceRepoUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	owner, repo, err := utils.ParseRepo(d.Get("repository").(string))
 
-	_, err = client.RepoUpdate(owner, repo, createRepo(d))
+	patch := createRepo(d)
+	if patch.Counter != nil {
+		current, err := client.Repo(owner, repo)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		patch = raiseCounterOnly(patch, current)
+	}
+
+	_, err = client.RepoUpdate(owner, repo, patch)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -170,7 +252,10 @@ func resourceRepoUpdate(ctx context.Context, d *schema.ResourceData, m interface
 }
 
 func resourceRepoDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -189,34 +274,155 @@ func resourceRepoDelete(ctx context.Context, d *schema.ResourceData, m interface
 	return diags
 }
 
+// resourceRepoCustomizeDiff checks during plan that settings which are
+// restricted to system admins are only changed with an admin token.
+func resourceRepoCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	counter := d.HasChange("build_counter") && d.Get("build_counter").(int) > 0
+
+	return checkRepoAdmin(ctx, d, m, counter)
+}
+
+// checkRepoAdmin checks during plan that trusted is only enabled and timeout
+// only raised above the maximum with an admin token. counter reports whether
+// the build counter is set, which also requires an admin token.
+func checkRepoAdmin(ctx context.Context, d *schema.ResourceDiff, m interface{}, counter bool) error {
+	trusted := d.HasChange("trusted") && d.Get("trusted").(bool)
+	timeout := d.HasChange("timeout") && d.Get("timeout").(int) > repoTimeoutMax
+
+	if !trusted && !timeout && !counter {
+		return nil
+	}
+
+	client, err := m.(*droneMeta).client(ctx, d.Get("server_profile").(string))
+	if err != nil {
+		return err
+	}
+
+	self, err := client.Self()
+	if err != nil {
+		return err
+	}
+
+	if self.Admin {
+		return nil
+	}
+
+	if trusted {
+		return fmt.Errorf("Error: trusted can only be enabled with an admin token, %s is not an admin", self.Login)
+	}
+
+	if timeout {
+		return fmt.Errorf("Error: timeout above %d minutes requires an admin token, %s is not an admin", repoTimeoutMax, self.Login)
+	}
+
+	// Drone silently ignores the counter in a patch from other users.
+	return fmt.Errorf("Error: build_counter can only be set with an admin token, %s is not an admin", self.Login)
+}
+
+// validateRepoConfiguration accepts a path relative to the repository root,
+// or an http(s) URL handled by a configuration extension.
+func validateRepoConfiguration(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://") {
+		return validation.IsURLWithHTTPorHTTPS(i, k)
+	}
+
+	cleaned := path.Clean(v)
+	if v == "" || path.IsAbs(v) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		errors = append(errors, fmt.Errorf("expected %q to be a path relative to the repository root (e.g. .drone.yml) or an http(s) URL, got %q", k, v))
+	}
+
+	return
+}
+
 func createRepo(data *schema.ResourceData) (repository *drone.RepoPatch) {
-	cancel_pulls := data.Get("cancel_pulls").(bool)
-	cancel_push := data.Get("cancel_push").(bool)
-	cancel_running := data.Get("cancel_running").(bool)
-	config := data.Get("configuration").(string)
-	ignore_forks := data.Get("ignore_forks").(bool)
-	ignore_pulls := data.Get("ignore_pulls").(bool)
-	protected := data.Get("protected").(bool)
-	timeout := int64(data.Get("timeout").(int))
-	trusted := data.Get("trusted").(bool)
-	visibility := data.Get("visibility").(string)
-
-	repository = &drone.RepoPatch{
-		CancelPulls:   &cancel_pulls,
-		CancelPush:    &cancel_push,
-		CancelRunning: &cancel_running,
-		Config:        &config,
-		IgnoreForks:   &ignore_forks,
-		IgnorePulls:   &ignore_pulls,
-		Protected:     &protected,
-		Trusted:       &trusted,
-		Timeout:       &timeout,
-		Visibility:    &visibility,
+	repository = repoPatch(data, func(key string) bool {
+		return settingConfigured(data, key) || data.HasChange(key)
+	})
+
+	if counter, ok := data.GetOk("build_counter"); ok && data.HasChange("build_counter") {
+		counter := int64(counter.(int))
+		repository.Counter = &counter
 	}
 
 	return
 }
 
+// settingConfigured reports whether a setting is set in the configuration.
+// During a refresh there is no configuration, so the state is used instead.
+func settingConfigured(d *schema.ResourceData, key string) bool {
+	if config := d.GetRawConfig(); !config.IsNull() {
+		return !config.GetAttr(key).IsNull()
+	}
+
+	if state := d.GetRawState(); !state.IsNull() {
+		return !state.GetAttr(key).IsNull()
+	}
+
+	return false
+}
+
+// repoPatch builds a patch containing only the repository settings for which
+// managed returns true, so settings owned by someone else are left alone.
+func repoPatch(d *schema.ResourceData, managed func(key string) bool) (repository *drone.RepoPatch) {
+	repository = &drone.RepoPatch{}
+
+	if managed("cancel_pulls") {
+		repository.CancelPulls = utils.Bool(d.Get("cancel_pulls").(bool))
+	}
+	if managed("cancel_push") {
+		repository.CancelPush = utils.Bool(d.Get("cancel_push").(bool))
+	}
+	if managed("cancel_running") {
+		repository.CancelRunning = utils.Bool(d.Get("cancel_running").(bool))
+	}
+	if managed("configuration") {
+		config := d.Get("configuration").(string)
+		repository.Config = &config
+	}
+	if managed("ignore_forks") {
+		repository.IgnoreForks = utils.Bool(d.Get("ignore_forks").(bool))
+	}
+	if managed("ignore_pulls") {
+		repository.IgnorePulls = utils.Bool(d.Get("ignore_pulls").(bool))
+	}
+	if managed("protected") {
+		repository.Protected = utils.Bool(d.Get("protected").(bool))
+	}
+	if managed("throttle") {
+		throttle := int64(d.Get("throttle").(int))
+		repository.Throttle = &throttle
+	}
+	if managed("timeout") {
+		timeout := int64(d.Get("timeout").(int))
+		repository.Timeout = &timeout
+	}
+	if managed("trusted") {
+		repository.Trusted = utils.Bool(d.Get("trusted").(bool))
+	}
+	if managed("visibility") {
+		visibility := d.Get("visibility").(string)
+		repository.Visibility = &visibility
+	}
+
+	return
+}
+
+// raiseCounterOnly drops the build counter from the patch when it would lower
+// the current counter and so reuse build numbers.
+func raiseCounterOnly(patch *drone.RepoPatch, current *drone.Repo) *drone.RepoPatch {
+	if patch.Counter != nil && *patch.Counter <= current.Counter {
+		patch.Counter = nil
+	}
+
+	return patch
+}
+
 func readRepo(d *schema.ResourceData, repository *drone.Repo) {
 	d.Set("cancel_pulls", repository.CancelPulls)
 	d.Set("cancel_push", repository.CancelPush)
@@ -226,7 +432,32 @@ func readRepo(d *schema.ResourceData, repository *drone.Repo) {
 	d.Set("ignore_pulls", repository.IgnorePulls)
 	d.Set("protected", repository.Protected)
 	d.Set("repository", fmt.Sprintf("%s/%s", repository.Namespace, repository.Name))
+	d.Set("throttle", repository.Throttle)
 	d.Set("timeout", repository.Timeout)
 	d.Set("trusted", repository.Trusted)
 	d.Set("visibility", repository.Visibility)
+
+	// The counter grows with every build, so it has only drifted when it is
+	// below the configured minimum.
+	if counter, ok := d.GetOk("build_counter"); ok && repository.Counter < int64(counter.(int)) {
+		d.Set("build_counter", repository.Counter)
+	}
+}
+
+// resourceRepoImport validates a [profile:]owner/repo import ID and sets the
+// repository attribute.
+func resourceRepoImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if err := importProfile(d, m); err != nil {
+		return nil, err
+	}
+
+	owner, repo, err := utils.ParseRepo(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", owner, repo))
+	d.Set("repository", d.Id())
+
+	return []*schema.ResourceData{d}, nil
 }
@@ -1,8 +1,13 @@
 package drone
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"terraform-provider-drone/drone/utils"
@@ -47,25 +52,87 @@ func TestAccDroneRepoBasic(t *testing.T) {
 						"timeout",
 						"60",
 					),
-					// these tests fail with "Attribute 'x' not found"
-					//					resource.TestCheckResourceAttr(
-					//						"drone_repo.new",
-					//						"protected",
-					//						"false",
-					//					),
-					//					resource.TestCheckResourceAttr(
-					//						"drone_repo.new",
-					//						"trusted",
-					//						"false",
-					//					),
+					resource.TestCheckResourceAttr(
+						"drone_repo.new",
+						"throttle",
+						"2",
+					),
+					resource.TestCheckResourceAttr(
+						"drone_repo.new",
+						"protected",
+						"false",
+					),
+					resource.TestCheckResourceAttr(
+						"drone_repo.new",
+						"trusted",
+						"false",
+					),
 				),
 			},
 		},
 	})
 }
 
+func TestValidateRepoConfiguration(t *testing.T) {
+	valid := []string{
+		".drone.yml",
+		"ci/pipeline.yaml",
+		"./.drone.star",
+		"https://config.example.com/pipeline",
+	}
+	for _, v := range valid {
+		if _, errs := validateRepoConfiguration(v, "configuration"); len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", v, errs)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"/etc/drone.yml",
+		"../other/.drone.yml",
+		"ci/../../.drone.yml",
+		"https://",
+	}
+	for _, v := range invalid {
+		if _, errs := validateRepoConfiguration(v, "configuration"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", v)
+		}
+	}
+}
+
+func TestResourceRepoCustomizeDiffBuildCounter(t *testing.T) {
+	for _, admin := range []bool{false, true} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(&drone.User{Login: "octocat", Admin: admin})
+		}))
+
+		state := &terraform.InstanceState{
+			ID: "octocat/hello-world",
+			Attributes: map[string]string{
+				"id":         "octocat/hello-world",
+				"repository": "octocat/hello-world",
+			},
+		}
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"repository":    "octocat/hello-world",
+			"build_counter": 100,
+		})
+		meta := newDroneMeta(serverSettings{Server: server.URL, Token: "token"}, nil)
+
+		_, err := resourceRepo().Diff(context.Background(), state, config, meta)
+		server.Close()
+
+		if admin && err != nil {
+			t.Errorf("expected no error for an admin, got %s", err)
+		}
+		if !admin && (err == nil || !strings.Contains(err.Error(), "build_counter can only be set with an admin token")) {
+			t.Errorf("expected an admin token error, got %v", err)
+		}
+	}
+}
+
 func testAccCheckDroneRepoDestroy(s *terraform.State) error {
-	c := testAccProvider.Meta().(drone.Client)
+	c := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "drone_repo" {
@@ -95,6 +162,7 @@ func testAccCheckDroneRepoConfigBasic(n string) string {
 	resource "drone_repo" "new" {
 		repository = "jimsheldon/drone-quickstart"
 		configuration = "%s.yaml"
+		throttle = 2
 	}
 	`, n)
 }
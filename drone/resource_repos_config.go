@@ -0,0 +1,337 @@
+package drone
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceReposConfig() *schema.Resource {
+	return &schema.Resource{
+		Description: "Resource for applying repository settings to every Drone repository matching a filter. Settings which are not set are left unchanged, and destroying the resource leaves the repositories as they are",
+		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
+			"last_updated": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"source": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  repoSourceList,
+				ValidateFunc: validation.StringInSlice([]string{
+					repoSourceSync,
+					repoSourceList,
+					repoSourceAll,
+				}, false),
+				Description: "How repositories are listed: `sync` refreshes the user's repositories from the host system, `list` returns the user's repositories without syncing, and `all` returns every repository on the server (admin only)",
+			},
+			"namespace": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				AtLeastOneOf: []string{"namespace", "name_regex"},
+				Description:  "Apply the settings to active repositories in this namespace",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				AtLeastOneOf: []string{"namespace", "name_regex"},
+				Description:  "Apply the settings to active repositories whose name matches this regular expression",
+			},
+			"parallelism": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      4,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of repositories updated concurrently",
+			},
+			"cancel_pulls": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"cancel_push": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"cancel_running": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"configuration": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRepoConfiguration,
+			},
+			"ignore_forks": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"ignore_pulls": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"protected": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"throttle": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(repoTimeoutMin),
+			},
+			"trusted": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"visibility": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(repoVisibilities, false),
+			},
+			"repositories": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Repositories matching the filter",
+			},
+			"drifted": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Repositories matching the filter whose settings differ from the configured settings",
+			},
+		},
+
+		Timeouts: defaultResourceTimeouts(),
+
+		CustomizeDiff: resourceReposConfigCustomizeDiff,
+
+		CreateContext: resourceReposConfigCreate,
+		ReadContext:   resourceReposConfigRead,
+		UpdateContext: resourceReposConfigUpdate,
+		DeleteContext: resourceReposConfigDelete,
+	}
+}
+
+func resourceReposConfigCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	repos, err := selectRepos(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if diags := updateRepos(client, repos, reposConfigPatch(d), d.Get("parallelism").(int)); diags.HasError() {
+		return diags
+	}
+
+	// The filters can change in place, so the ID does not depend on them.
+	d.SetId(resource.UniqueId())
+
+	return resourceReposConfigRead(ctx, d, m)
+}
+
+func resourceReposConfigRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	repos, err := selectRepos(client, d)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Failed to retrieve repositories",
+			Detail:   err.Error(),
+		})
+
+		return diags
+	}
+
+	slugs := make([]string, 0)
+	drifted := make([]string, 0)
+
+	for _, repo := range repos {
+		slugs = append(slugs, repo.Slug)
+
+		for key, setting := range repoSettings {
+			if settingConfigured(d, key) && setting(repo) != d.Get(key) {
+				drifted = append(drifted, repo.Slug)
+				break
+			}
+		}
+	}
+
+	d.Set("repositories", slugs)
+	d.Set("drifted", drifted)
+
+	return diags
+}
+
+func resourceReposConfigUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	repos, err := selectRepos(client, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if diags := updateRepos(client, repos, reposConfigPatch(d), d.Get("parallelism").(int)); diags.HasError() {
+		return diags
+	}
+
+	d.Set("last_updated", time.Now().Format(time.RFC850))
+
+	return resourceReposConfigRead(ctx, d, m)
+}
+
+func resourceReposConfigDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+
+	// Repository settings cannot be reverted, so they are left as they are.
+
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+
+	return diags
+}
+
+// resourceReposConfigCustomizeDiff checks that settings restricted to system
+// admins are only changed with an admin token, and plans an update when any
+// repository has drifted from the configured settings, so the drifted
+// repositories are reported in the plan.
+func resourceReposConfigCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if err := checkRepoAdmin(ctx, d, m, false); err != nil {
+		return err
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+
+	if d.HasChanges("source", "namespace", "name_regex") {
+		if err := d.SetNewComputed("repositories"); err != nil {
+			return err
+		}
+	}
+
+	if drifted, _ := d.GetChange("drifted"); len(drifted.([]interface{})) > 0 {
+		return d.SetNew("drifted", []string{})
+	}
+
+	return nil
+}
+
+// resourceGetter reads attributes from either a schema.ResourceData or a
+// schema.ResourceDiff.
+type resourceGetter interface {
+	Get(key string) interface{}
+	GetOk(key string) (interface{}, bool)
+}
+
+// selectRepos returns the active repositories matching the namespace and
+// name_regex filters, sorted by slug.
+func selectRepos(client drone.Client, d resourceGetter) ([]*drone.Repo, error) {
+	repos, err := listRepos(client, d.Get("source").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := d.Get("namespace").(string)
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+
+	selected := make([]*drone.Repo, 0)
+	for _, repo := range repos {
+		if !repo.Active {
+			continue
+		}
+		if namespace != "" && repo.Namespace != namespace {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(repo.Name) {
+			continue
+		}
+
+		selected = append(selected, repo)
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Slug < selected[j].Slug
+	})
+
+	return selected, nil
+}
+
+// updateRepos applies patch to repos, running at most parallelism updates
+// at a time.
+func updateRepos(client drone.Client, repos []*drone.Repo, patch *drone.RepoPatch, parallelism int) diag.Diagnostics {
+	var (
+		diags diag.Diagnostics
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+	)
+
+	sem := make(chan struct{}, parallelism)
+
+	for _, repo := range repos {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(repo *drone.Repo) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if _, err := client.RepoUpdate(repo.Namespace, repo.Name, patch); err != nil {
+				mu.Lock()
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  fmt.Sprintf("Failed to update Drone Repo: %s", repo.Slug),
+					Detail:   err.Error(),
+				})
+				mu.Unlock()
+			}
+		}(repo)
+	}
+
+	wg.Wait()
+
+	return diags
+}
+
+// reposConfigPatch builds a patch containing only the configured settings.
+func reposConfigPatch(d *schema.ResourceData) *drone.RepoPatch {
+	return repoPatch(d, func(key string) bool {
+		return settingConfigured(d, key)
+	})
+}
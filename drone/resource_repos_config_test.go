@@ -0,0 +1,109 @@
+package drone
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDroneReposConfigBasic(t *testing.T) {
+	// testing requires a valid repository, currently I only have this working
+	// in my own local environment
+	scmAvail := os.Getenv("SCM_AVAIL")
+	if scmAvail == "" {
+		t.Skip("set SCM_AVAIL to run this test")
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDroneReposConfigConfigBasic(testDroneUser, 30),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDroneReposConfigExists("drone_repos_config.policy"),
+					resource.TestCheckResourceAttr(
+						"drone_repos_config.policy",
+						"drifted.#",
+						"0",
+					),
+				),
+			},
+			{
+				Config: testAccCheckDroneReposConfigConfigBasic(testDroneUser, 45),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"drone_repos_config.policy",
+						"timeout",
+						"45",
+					),
+					resource.TestCheckResourceAttr(
+						"drone_repos_config.policy",
+						"drifted.#",
+						"0",
+					),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceReposConfigCustomizeDiffTrusted(t *testing.T) {
+	for _, admin := range []bool{false, true} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(&drone.User{Login: "octocat", Admin: admin})
+		}))
+
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"namespace": "octocat",
+			"trusted":   true,
+		})
+		meta := newDroneMeta(serverSettings{Server: server.URL, Token: "token"}, nil)
+
+		_, err := resourceReposConfig().Diff(context.Background(), nil, config, meta)
+		server.Close()
+
+		if admin && err != nil {
+			t.Errorf("expected no error for an admin, got %s", err)
+		}
+		if !admin && (err == nil || !strings.Contains(err.Error(), "trusted can only be enabled with an admin token")) {
+			t.Errorf("expected an admin token error, got %v", err)
+		}
+	}
+}
+
+func testAccCheckDroneReposConfigConfigBasic(namespace string, timeout int) string {
+	return fmt.Sprintf(`
+	resource "drone_repos_config" "policy" {
+		namespace    = "%s"
+		timeout      = %d
+		cancel_pulls = true
+		ignore_forks = true
+	}
+	`, namespace, timeout)
+}
+
+func testAccCheckDroneReposConfigExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID set")
+		}
+
+		return nil
+	}
+}
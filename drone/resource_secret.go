@@ -18,6 +18,7 @@ func resourceSecret() *schema.Resource {
 	return &schema.Resource{
 		Description: "Resource for creating a Drone repository secret",
 		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
 			"last_updated": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -38,10 +39,12 @@ func resourceSecret() *schema.Resource {
 				ForceNew: true,
 			},
 			"value": {
-				Type:      schema.TypeString,
-				Required:  true,
-				Sensitive: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"value", "docker_config"},
 			},
+			"docker_config": dockerConfigSchema(),
 			"allow_on_pull_request": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -55,9 +58,13 @@ func resourceSecret() *schema.Resource {
 		},
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceSecretImport,
 		},
 
+		Timeouts: defaultResourceTimeouts(),
+
+		CustomizeDiff: secretCustomizeDiff,
+
 		CreateContext: resourceSecretCreate,
 		ReadContext:   resourceSecretRead,
 		UpdateContext: resourceSecretUpdate,
@@ -66,7 +73,10 @@ func resourceSecret() *schema.Resource {
 }
 
 func resourceSecretCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -87,7 +97,10 @@ func resourceSecretCreate(ctx context.Context, d *schema.ResourceData, m interfa
 }
 
 func resourceSecretRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -114,7 +127,10 @@ func resourceSecretRead(ctx context.Context, d *schema.ResourceData, m interface
 }
 
 func resourceSecretUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	owner, repo, err := utils.ParseRepo(d.Get("repository").(string))
 	if err != nil {
@@ -132,7 +148,10 @@ func resourceSecretUpdate(ctx context.Context, d *schema.ResourceData, m interfa
 }
 
 func resourceSecretDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -157,7 +176,7 @@ func resourceSecretDelete(ctx context.Context, d *schema.ResourceData, m interfa
 func createSecret(d *schema.ResourceData) (secret *drone.Secret) {
 	secret = &drone.Secret{
 		Name:            d.Get("name").(string),
-		Data:            d.Get("value").(string),
+		Data:            secretValue(d),
 		PullRequest:     d.Get("allow_on_pull_request").(bool),
 		PullRequestPush: d.Get("allow_push_on_pull_request").(bool),
 	}
@@ -171,3 +190,22 @@ func readSecret(d *schema.ResourceData, owner, repo string, secret *drone.Secret
 	d.Set("allow_on_pull_request", secret.PullRequest)
 	d.Set("allow_push_on_pull_request", secret.PullRequestPush)
 }
+
+// resourceSecretImport validates a [profile:]owner/repo/secret_name import ID
+// and sets the attributes identifying the secret.
+func resourceSecretImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if err := importProfile(d, m); err != nil {
+		return nil, err
+	}
+
+	owner, repo, name, err := utils.ParseId(d.Id(), "secret_name")
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", owner, repo, name))
+	d.Set("repository", fmt.Sprintf("%s/%s", owner, repo))
+	d.Set("name", name)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -7,7 +7,6 @@ import (
 
 	"terraform-provider-drone/drone/utils"
 
-	"github.com/drone/drone-go/drone"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -60,7 +59,7 @@ func TestAccDroneSecretBasic(t *testing.T) {
 }
 
 func testAccCheckDroneSecretDestroy(s *terraform.State) error {
-	c := testAccProvider.Meta().(drone.Client)
+	c := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "drone_secret" {
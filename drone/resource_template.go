@@ -15,14 +15,17 @@ func resourceTemplate() *schema.Resource {
 	return &schema.Resource{
 		Description: "Resource for creating a Drone template",
 		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
 			"last_updated": {
 				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
 			},
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateTemplateName,
+				Description:  "Template name, its extension selects the template format: `.yaml` or `.yml` for YAML, `.jsonnet` for Jsonnet and `.star`, `.starlark` or `.script` for Starlark",
 			},
 			"namespace": {
 				Type:     schema.TypeString,
@@ -30,15 +33,26 @@ func resourceTemplate() *schema.Resource {
 				ForceNew: true,
 			},
 			"data": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Template content, which is checked during plan to be a valid template in the format selected by the name",
+			},
+			"replace_existing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Adopt a template which already exists with the same name instead of failing, replacing its content",
 			},
 		},
 
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceTemplateImport,
 		},
 
+		Timeouts: defaultResourceTimeouts(),
+
+		CustomizeDiff: resourceTemplateCustomizeDiff,
+
 		CreateContext: resourceTemplateCreate,
 		ReadContext:   resourceTemplateRead,
 		UpdateContext: resourceTemplateUpdate,
@@ -47,25 +61,31 @@ func resourceTemplate() *schema.Resource {
 }
 
 func resourceTemplateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
 	namespace := d.Get("namespace").(string)
 
-	template, err := client.TemplateCreate(namespace, createTemplate(d))
+	err = createOrAdoptTemplate(client, d, namespace)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId(fmt.Sprintf("%s/%s", namespace, template.Name))
+	d.SetId(fmt.Sprintf("%s/%s", namespace, d.Get("name").(string)))
 
 	return diags
 }
 
 func resourceTemplateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -86,31 +106,57 @@ func resourceTemplateRead(ctx context.Context, d *schema.ResourceData, m interfa
 		return diags
 	}
 
-	readTemplate(d, template)
+	readTemplate(d, namespace, template)
 
 	return diags
 }
 
 func resourceTemplateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
-
-	namespace, name, err := utils.ParseOrgId(d.Id(), "template_name")
+	client, err := m.(*droneMeta).clientFor(ctx, d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	_, err = client.TemplateUpdate(namespace, name, createTemplate(d))
+	namespace, name, err := utils.ParseOrgId(d.Id(), "template_name")
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	// Drone cannot rename a template, so a renamed template is created under
+	// its new name before the old one is deleted.
+	if d.HasChange("name") {
+		err = createOrAdoptTemplate(client, d, namespace)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		d.SetId(fmt.Sprintf("%s/%s", namespace, d.Get("name").(string)))
+
+		err = client.TemplateDelete(namespace, name)
+		if err != nil {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Failed to delete renamed Drone Template: %s/%s", namespace, name),
+				Detail:   err.Error(),
+			}}
+		}
+	} else {
+		_, err = client.TemplateUpdate(namespace, name, createTemplate(d))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	d.Set("last_updated", time.Now().Format(time.RFC850))
 
 	return resourceTemplateRead(ctx, d, m)
 }
 
 func resourceTemplateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -132,6 +178,41 @@ func resourceTemplateDelete(ctx context.Context, d *schema.ResourceData, m inter
 	return diags
 }
 
+// resourceTemplateCustomizeDiff checks during plan that the template data
+// is valid in the format selected by the template name.
+func resourceTemplateCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("name") || !d.NewValueKnown("data") {
+		return nil
+	}
+
+	name := d.Get("name").(string)
+
+	if err := validateTemplateData(name, d.Get("data").(string)); err != nil {
+		return fmt.Errorf("Error: Invalid template %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// createOrAdoptTemplate creates the configured template. When
+// replace_existing is set and the template already exists, its content is
+// replaced instead.
+func createOrAdoptTemplate(client drone.Client, d *schema.ResourceData, namespace string) error {
+	var err error
+
+	name := d.Get("name").(string)
+
+	if d.Get("replace_existing").(bool) {
+		if _, err = client.Template(namespace, name); err == nil {
+			_, err = client.TemplateUpdate(namespace, name, createTemplate(d))
+			return err
+		}
+	}
+
+	_, err = client.TemplateCreate(namespace, createTemplate(d))
+	return err
+}
+
 func createTemplate(d *schema.ResourceData) (template *drone.Template) {
 	template = &drone.Template{
 		Name: d.Get("name").(string),
@@ -141,7 +222,28 @@ func createTemplate(d *schema.ResourceData) (template *drone.Template) {
 	return
 }
 
-func readTemplate(d *schema.ResourceData, template *drone.Template) {
+func readTemplate(d *schema.ResourceData, namespace string, template *drone.Template) {
+	d.Set("namespace", namespace)
 	d.Set("name", template.Name)
 	d.Set("data", template.Data)
 }
+
+// resourceTemplateImport validates a [profile:]namespace/template_name import
+// ID and sets the attributes identifying the template.
+func resourceTemplateImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if err := importProfile(d, m); err != nil {
+		return nil, err
+	}
+
+	namespace, name, err := utils.ParseOrgId(d.Id(), "template_name")
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", namespace, name))
+	d.Set("namespace", namespace)
+	d.Set("name", name)
+	d.Set("replace_existing", false)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -44,12 +44,106 @@ func TestAccDroneTemplateBasic(t *testing.T) {
 					),
 				),
 			},
+			{
+				ResourceName:      "drone_template.template",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccDroneTemplateRename(t *testing.T) {
+	// generate a random name to avoid collisions from multiple concurrent tests.
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	oldName := fmt.Sprintf("%s-old.yaml", rName)
+	newName := fmt.Sprintf("%s-new.yaml", rName)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDroneTemplateDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDroneTemplateConfigBasic("test", oldName, "kind: pipeline"),
+				Check: resource.TestCheckResourceAttr(
+					"drone_template.template",
+					"id",
+					fmt.Sprintf("test/%s", oldName),
+				),
+			},
+			{
+				Config: testAccCheckDroneTemplateConfigBasic("test", newName, "kind: pipeline"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"drone_template.template",
+						"id",
+						fmt.Sprintf("test/%s", newName),
+					),
+					resource.TestCheckResourceAttr(
+						"drone_template.template",
+						"name",
+						newName,
+					),
+					testAccCheckDroneTemplateMissing("test", oldName),
+				),
+			},
 		},
 	})
 }
 
+func TestAccDroneTemplateReplaceExisting(t *testing.T) {
+	// generate a random name to avoid collisions from multiple concurrent tests.
+	rName := fmt.Sprintf("%s.yaml", acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDroneTemplateDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					_, err := testAccClient().TemplateCreate("test", &drone.Template{
+						Name: rName,
+						Data: "kind: secret",
+					})
+					if err != nil {
+						t.Fatalf("failed to create existing template: %s", err)
+					}
+				},
+				Config: fmt.Sprintf(`
+	resource "drone_template" "template" {
+		namespace        = "test"
+		name             = "%s"
+		data             = "kind: pipeline"
+		replace_existing = true
+	}
+	`, rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDroneTemplateExists("drone_template.template"),
+					resource.TestCheckResourceAttr(
+						"drone_template.template",
+						"data",
+						"kind: pipeline",
+					),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDroneTemplateMissing(namespace, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if _, err := testAccClient().Template(namespace, name); err == nil {
+			return fmt.Errorf("Template (%s/%s) still exists.", namespace, name)
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckDroneTemplateDestroy(s *terraform.State) error {
-	c := testAccProvider.Meta().(drone.Client)
+	c := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "drone_template" {
@@ -3,6 +3,8 @@ package drone
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"terraform-provider-drone/drone/utils"
@@ -16,6 +18,7 @@ func resourceUser() *schema.Resource {
 	return &schema.Resource{
 		Description: "Resource for creating a Drone user",
 		Schema: map[string]*schema.Schema{
+			"server_profile": serverProfileSchema(),
 			"last_updated": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -40,15 +43,59 @@ func resourceUser() *schema.Resource {
 				Default:  false,
 			},
 			"token": {
-				Type:     schema.TypeString,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"token_rotation"},
+				Description:   "API token for the user. When not set, Drone generates a token, which is only returned for machine users",
+			},
+			"token_rotation": {
+				Type:          schema.TypeMap,
+				Optional:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"token"},
+				Description:   "Arbitrary map of values which, when changed, generates a new token for the user",
+			},
+			"email": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Email address of the user. Only set when the user is created, since Drone updates it from the host system when the user logs in",
+			},
+			"avatar": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Avatar URL of the user. Only set when the user is created, since Drone updates it from the host system when the user logs in",
+			},
+			"created": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"last_login": {
+				Type:     schema.TypeInt,
 				Computed: true,
 			},
+			"synced": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"transfer_repos_to": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Login which takes ownership of the user's active repositories before the user is deleted. Drone transfers ownership to the authenticated user, so the provider token or the token of a `server_profile` for the same server must belong to this login. When not set, deleting a user which owns active repositories fails",
+			},
 		},
 
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: resourceUserImport,
 		},
 
+		Timeouts: defaultResourceTimeouts(),
+
+		CustomizeDiff: resourceUserCustomizeDiff,
+
 		CreateContext: resourceUserCreate,
 		ReadContext:   resourceUserRead,
 		UpdateContext: resourceUserUpdate,
@@ -57,7 +104,10 @@ func resourceUser() *schema.Resource {
 }
 
 func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -68,12 +118,16 @@ func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface
 	}
 
 	d.SetId(user.Login)
+	readUser(d, user)
 
-	return diags
+	return append(diags, resourceUserRead(ctx, d, m)...)
 }
 
 func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
@@ -95,7 +149,10 @@ func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}
 }
 
 func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	user, err := client.User(d.Id())
 	if err != nil {
@@ -107,20 +164,77 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface
 		return diag.FromErr(err)
 	}
 
+	if d.HasChange("token_rotation") {
+		api, err := m.(*droneMeta).apiClientFor(ctx, d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		rotated, err := api.UserTokenRotate(user.Login)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		d.Set("token", rotated.Token)
+	}
+
 	d.Set("last_updated", time.Now().Format(time.RFC850))
 
 	return resourceUserRead(ctx, d, m)
 }
 
+// resourceUserCustomizeDiff plans a new token when token_rotation changes, so
+// that references to the token are updated in the same apply. Drone cannot
+// update the email and avatar of a user, so changes to them are ignored once
+// the user exists.
+func resourceUserCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	for _, key := range []string{"email", "avatar"} {
+		if d.HasChange(key) {
+			if err := d.Clear(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.HasChange("token_rotation") {
+		return d.SetNewComputed("token")
+	}
+
+	return nil
+}
+
 func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(drone.Client)
+	client, err := m.(*droneMeta).clientFor(ctx, d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
 	login := d.Get("login").(string)
 
-	err := client.UserDelete(login)
+	user, err := client.User(login)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	repos, err := ownedRepos(client, user)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(repos) > 0 {
+		if diags = transferRepos(ctx, m.(*droneMeta), d, user, repos); diags.HasError() {
+			return diags
+		}
+	}
+
+	err = client.UserDelete(login)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -132,12 +246,136 @@ func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m interface
 	return diags
 }
 
+// ownedRepos returns the active repositories owned by user. Builds for these
+// repositories stop working once their owner is deleted.
+func ownedRepos(client drone.Client, user *drone.User) ([]*drone.Repo, error) {
+	repos, err := repoListAll(client)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make([]*drone.Repo, 0)
+	for _, repo := range repos {
+		if repo.Active && repo.UserID == user.ID {
+			owned = append(owned, repo)
+		}
+	}
+
+	return owned, nil
+}
+
+// transferRepos reassigns repos to the transfer_repos_to login, or fails
+// with the list of repos when no login is configured. Drone transfers
+// ownership to the authenticated user, so the repos are transferred with
+// whichever configured token for the same server belongs to the login.
+func transferRepos(ctx context.Context, meta *droneMeta, d *schema.ResourceData, user *drone.User, repos []*drone.Repo) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	slugs := make([]string, 0)
+	for _, repo := range repos {
+		slugs = append(slugs, repo.Slug)
+	}
+	sort.Strings(slugs)
+
+	target := d.Get("transfer_repos_to").(string)
+	if target == "" {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Drone user %s owns active repositories", user.Login),
+			Detail: fmt.Sprintf(
+				"Deleting the user would orphan these repositories: %s. Set transfer_repos_to to reassign them before the user is deleted.",
+				strings.Join(slugs, ", "),
+			),
+		})
+
+		return diags
+	}
+
+	client, err := profileClientFor(ctx, meta, d.Get("server_profile").(string), target)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if client == nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Unable to transfer repositories to %s", target),
+			Detail: fmt.Sprintf(
+				"Drone transfers repository ownership to the authenticated user, but neither the provider token nor the token of a server_profile for the same server belongs to %s. Add a server_profile with a token for %s to the provider configuration.",
+				target,
+				target,
+			),
+		})
+
+		return diags
+	}
+
+	for _, repo := range repos {
+		if _, err := client.RepoChown(repo.Namespace, repo.Name); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Failed to transfer Drone Repo: %s", repo.Slug),
+				Detail:   err.Error(),
+			})
+
+			return diags
+		}
+	}
+
+	return diags
+}
+
+// profileClientFor returns a client authenticated as login on the server of
+// profile, using the default server or any server_profile with the same
+// address. It returns nil when no configured token belongs to login.
+func profileClientFor(ctx context.Context, meta *droneMeta, profile, login string) (drone.Client, error) {
+	settings, err := meta.settings(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// The default server is tried first, then the profiles in name order.
+	names := []string{""}
+	for name := range meta.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+
+	for _, name := range names {
+		candidate, _ := meta.settings(name)
+		if candidate.Server != settings.Server || candidate.Token == "" {
+			continue
+		}
+
+		client, err := meta.client(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		self, err := client.Self()
+		if err != nil {
+			// A token for another user may be invalid without affecting
+			// the transfer.
+			continue
+		}
+
+		if self.Login == login {
+			return client, nil
+		}
+	}
+
+	return nil, nil
+}
+
 func createUser(d *schema.ResourceData) (user *drone.User) {
 	user = &drone.User{
 		Login:   d.Get("login").(string),
+		Email:   d.Get("email").(string),
+		Avatar:  d.Get("avatar").(string),
 		Active:  d.Get("active").(bool),
 		Admin:   d.Get("admin").(bool),
 		Machine: d.Get("machine").(bool),
+		Token:   d.Get("token").(string),
 	}
 
 	return
@@ -150,6 +388,10 @@ func updateUser(data *schema.ResourceData) (userPatch *drone.UserPatch) {
 		Machine: utils.Bool(data.Get("machine").(bool)),
 	}
 
+	if token := data.Get("token").(string); data.HasChange("token") && token != "" {
+		userPatch.Token = &token
+	}
+
 	return
 }
 
@@ -158,5 +400,32 @@ func readUser(d *schema.ResourceData, user *drone.User) {
 	d.Set("active", user.Active)
 	d.Set("machine", user.Machine)
 	d.Set("admin", user.Admin)
-	d.Set("token", user.Token)
+	d.Set("email", user.Email)
+	d.Set("avatar", user.Avatar)
+	d.Set("created", user.Created)
+	d.Set("last_login", user.LastLogin)
+	d.Set("synced", user.Synced)
+
+	// Drone only returns the token when the user is created or its token is
+	// rotated, so keep the value already in state otherwise.
+	if user.Token != "" {
+		d.Set("token", user.Token)
+	}
+}
+
+// resourceUserImport validates a [profile:]login import ID and sets the login
+// attribute.
+func resourceUserImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if err := importProfile(d, m); err != nil {
+		return nil, err
+	}
+
+	login, err := utils.ParseLogin(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("login", login)
+
+	return []*schema.ResourceData{d}, nil
 }
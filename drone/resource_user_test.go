@@ -1,12 +1,18 @@
 package drone
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/drone/drone-go/drone"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -34,8 +40,217 @@ func TestAccDroneUserBasic(t *testing.T) {
 	})
 }
 
+func TestAccDroneUserMachine(t *testing.T) {
+	// generate a random name to avoid collisions from multiple concurrent tests.
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDroneUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDroneUserConfigMachine(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDroneUserExists("drone_user.user"),
+					resource.TestCheckResourceAttrSet(
+						"drone_user.user",
+						"token",
+					),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDroneUserPresetToken(t *testing.T) {
+	// generate a random name to avoid collisions from multiple concurrent tests.
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	token := acctest.RandStringFromCharSet(32, acctest.CharSetAlphaNum)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDroneUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDroneUserConfigPresetToken(rName, token),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDroneUserExists("drone_user.user"),
+					resource.TestCheckResourceAttr(
+						"drone_user.user",
+						"email",
+						fmt.Sprintf("%s@example.com", rName),
+					),
+					resource.TestCheckResourceAttr(
+						"drone_user.user",
+						"token",
+						token,
+					),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDroneUserTokenRotation(t *testing.T) {
+	// generate a random name to avoid collisions from multiple concurrent tests.
+	rName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+
+	var token string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDroneUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDroneUserConfigTokenRotation(rName, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDroneUserExists("drone_user.user"),
+					testAccCheckDroneUserToken("drone_user.user", &token, false),
+				),
+			},
+			{
+				Config: testAccCheckDroneUserConfigTokenRotation(rName, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDroneUserToken("drone_user.user", &token, false),
+				),
+			},
+			{
+				Config: testAccCheckDroneUserConfigTokenRotation(rName, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDroneUserToken("drone_user.user", &token, true),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceUserCustomizeDiff(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "octocat",
+		Attributes: map[string]string{
+			"id":                      "octocat",
+			"login":                   "octocat",
+			"active":                  "true",
+			"admin":                   "false",
+			"machine":                 "true",
+			"email":                   "octocat@example.com",
+			"avatar":                  "https://example.com/octocat.png",
+			"token":                   "secret",
+			"token_rotation.%":        "1",
+			"token_rotation.rotation": "1",
+		},
+	}
+
+	for _, tc := range []struct {
+		config   map[string]interface{}
+		computed []string
+		unset    []string
+	}{
+		{
+			config: map[string]interface{}{
+				"login":          "octocat",
+				"active":         true,
+				"admin":          false,
+				"machine":        true,
+				"email":          "hubot@example.com",
+				"avatar":         "https://example.com/hubot.png",
+				"token_rotation": map[string]interface{}{"rotation": "1"},
+			},
+			unset: []string{"email", "avatar", "token"},
+		},
+		{
+			config: map[string]interface{}{
+				"login":          "octocat",
+				"active":         true,
+				"admin":          false,
+				"machine":        true,
+				"token_rotation": map[string]interface{}{"rotation": "2"},
+			},
+			computed: []string{"token"},
+		},
+	} {
+		diff, err := resourceUser().Diff(context.Background(), state, terraform.NewResourceConfigRaw(tc.config), nil)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if diff == nil {
+			diff = &terraform.InstanceDiff{}
+		}
+
+		for _, key := range tc.computed {
+			if attr, ok := diff.Attributes[key]; !ok || !attr.NewComputed {
+				t.Errorf("expected %s to be planned as computed", key)
+			}
+		}
+		for _, key := range tc.unset {
+			if _, ok := diff.Attributes[key]; ok {
+				t.Errorf("expected no change to %s", key)
+			}
+		}
+		if diff.RequiresNew() {
+			t.Errorf("expected an update, got a replacement")
+		}
+	}
+}
+
+func TestTransferRepos(t *testing.T) {
+	logins := map[string]string{
+		"Bearer admin-token":   "admin",
+		"Bearer octocat-token": "octocat",
+	}
+	chowned := make(map[string]string)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		login := logins[r.Header.Get("Authorization")]
+
+		switch {
+		case r.URL.Path == "/api/user":
+			json.NewEncoder(w).Encode(&drone.User{Login: login})
+		case strings.HasSuffix(r.URL.Path, "/chown"):
+			chowned[r.URL.Path] = login
+			json.NewEncoder(w).Encode(&drone.Repo{})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	meta := newDroneMeta(
+		serverSettings{Server: server.URL, Token: "admin-token"},
+		map[string]serverSettings{
+			"octocat": {Server: server.URL, Token: "octocat-token"},
+			"other":   {Server: "http://drone.invalid", Token: "octocat-token"},
+		},
+	)
+	user := &drone.User{Login: "hubot"}
+	repos := []*drone.Repo{{Namespace: "hubot", Name: "hello-world", Slug: "hubot/hello-world"}}
+
+	d := schema.TestResourceDataRaw(t, resourceUser().Schema, map[string]interface{}{
+		"login":             "hubot",
+		"transfer_repos_to": "octocat",
+	})
+	if diags := transferRepos(context.Background(), meta, d, user, repos); diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+	if login := chowned["/api/repos/hubot/hello-world/chown"]; login != "octocat" {
+		t.Errorf("expected the repository to be transferred to octocat, got %q", login)
+	}
+
+	d = schema.TestResourceDataRaw(t, resourceUser().Schema, map[string]interface{}{
+		"login":             "hubot",
+		"transfer_repos_to": "monalisa",
+	})
+	diags := transferRepos(context.Background(), meta, d, user, repos)
+	if !diags.HasError() || !strings.Contains(diags[0].Summary, "Unable to transfer repositories to monalisa") {
+		t.Errorf("expected an error for a login without a token, got %v", diags)
+	}
+}
+
 func testAccCheckDroneUserDestroy(s *terraform.State) error {
-	c := testAccProvider.Meta().(drone.Client)
+	c := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "drone_user" {
@@ -63,6 +278,70 @@ func testAccCheckDroneUserConfigBasic(n string) string {
 	`, n)
 }
 
+func testAccCheckDroneUserConfigMachine(n string) string {
+	return fmt.Sprintf(`
+	resource "drone_user" "user" {
+		login = "%s"
+		active = true
+		admin = false
+		machine = true
+	}
+	`, n)
+}
+
+func testAccCheckDroneUserConfigPresetToken(n, token string) string {
+	return fmt.Sprintf(`
+	resource "drone_user" "user" {
+		login = "%s"
+		email = "%s@example.com"
+		active = true
+		admin = false
+		machine = true
+		token = "%s"
+	}
+	`, n, n, token)
+}
+
+func testAccCheckDroneUserConfigTokenRotation(n, rotation string) string {
+	return fmt.Sprintf(`
+	resource "drone_user" "user" {
+		login = "%s"
+		active = true
+		admin = false
+		machine = true
+
+		token_rotation = {
+			rotation = "%s"
+		}
+	}
+	`, n, rotation)
+}
+
+// testAccCheckDroneUserToken checks that the user has a token, and whether it
+// changed since the previous check.
+func testAccCheckDroneUserToken(n string, previous *string, changed bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		token := rs.Primary.Attributes["token"]
+		if token == "" {
+			return fmt.Errorf("No token set")
+		}
+
+		if *previous != "" && (token != *previous) != changed {
+			return fmt.Errorf("Expected token changed to be %t", changed)
+		}
+
+		*previous = token
+
+		return nil
+	}
+}
+
 func testAccCheckDroneUserExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
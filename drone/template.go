@@ -0,0 +1,314 @@
+package drone
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/google/go-jsonnet"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkjson"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+	"gopkg.in/yaml.v3"
+)
+
+// Template formats, selected by the extension of the template name as in
+// Drone.
+const (
+	templateFormatYAML     = "yaml"
+	templateFormatJsonnet  = "jsonnet"
+	templateFormatStarlark = "starlark"
+)
+
+var templateExtensions = map[string]string{
+	".yaml":     templateFormatYAML,
+	".yml":      templateFormatYAML,
+	".jsonnet":  templateFormatJsonnet,
+	".star":     templateFormatStarlark,
+	".starlark": templateFormatStarlark,
+	".script":   templateFormatStarlark,
+}
+
+// templateFormat returns the format of the named template.
+func templateFormat(name string) (string, error) {
+	format, ok := templateExtensions[strings.ToLower(path.Ext(name))]
+	if !ok {
+		extensions := make([]string, 0, len(templateExtensions))
+		for ext := range templateExtensions {
+			extensions = append(extensions, ext)
+		}
+		sort.Strings(extensions)
+
+		return "", fmt.Errorf("Error: Invalid template name %q, its extension must be one of %s", name, strings.Join(extensions, ", "))
+	}
+
+	return format, nil
+}
+
+func validateTemplateName(v interface{}, k string) (warnings []string, errs []error) {
+	if _, err := templateFormat(v.(string)); err != nil {
+		errs = append(errs, err)
+	}
+
+	return
+}
+
+// validateTemplateData checks that data is a well formed template in the
+// format selected by name. Parts of a template which depend on its inputs
+// cannot be checked without them, so YAML documents are only parsed when the
+// template renders without inputs and Jsonnet templates may reference
+// undefined external variables.
+func validateTemplateData(name, data string) error {
+	format, err := templateFormat(name)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case templateFormatYAML:
+		tmpl, err := newYAMLTemplate(name, data)
+		if err != nil {
+			return err
+		}
+
+		out, err := executeYAMLTemplate(tmpl, nil)
+		if err != nil {
+			return nil
+		}
+
+		return validateYAML(out)
+	case templateFormatJsonnet:
+		_, err := evaluateJsonnet(name, data, nil)
+		if err != nil && strings.Contains(err.Error(), "Undefined external variable") {
+			return nil
+		}
+
+		return err
+	default:
+		_, err := syntax.Parse(name, data, 0)
+		return err
+	}
+}
+
+// renderTemplate expands the named template with inputs the way Drone does
+// when a pipeline extends it, and returns the resulting pipeline documents.
+func renderTemplate(name, data string, inputs map[string]interface{}) (string, error) {
+	format, err := templateFormat(name)
+	if err != nil {
+		return "", err
+	}
+
+	var docs []string
+
+	switch format {
+	case templateFormatYAML:
+		tmpl, err := newYAMLTemplate(name, data)
+		if err != nil {
+			return "", err
+		}
+
+		out, err := executeYAMLTemplate(tmpl, inputs)
+		if err != nil {
+			return "", err
+		}
+
+		if err := validateYAML(out); err != nil {
+			return "", err
+		}
+
+		return out, nil
+	case templateFormatJsonnet:
+		docs, err = evaluateJsonnet(name, data, inputs)
+	default:
+		docs, err = executeStarlark(name, data, inputs)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	for _, doc := range docs {
+		buf.WriteString("---\n")
+		buf.WriteString(strings.TrimSpace(doc))
+		buf.WriteString("\n")
+	}
+
+	return buf.String(), nil
+}
+
+// newYAMLTemplate parses a YAML template with the functions Drone makes
+// available to templates.
+func newYAMLTemplate(name, data string) (*template.Template, error) {
+	funcs := sprig.TxtFuncMap()
+
+	// Drone does not expose the environment to templates.
+	delete(funcs, "env")
+	delete(funcs, "expandenv")
+
+	return template.New(name).Funcs(funcs).Parse(data)
+}
+
+func executeYAMLTemplate(tmpl *template.Template, inputs map[string]interface{}) (string, error) {
+	var buf bytes.Buffer
+
+	err := tmpl.Execute(&buf, map[string]interface{}{
+		"build": map[string]interface{}{},
+		"repo":  map[string]interface{}{},
+		"input": inputs,
+	})
+
+	return buf.String(), err
+}
+
+// validateYAML checks that every document in data parses.
+func validateYAML(data string) error {
+	decoder := yaml.NewDecoder(strings.NewReader(data))
+
+	for {
+		var doc interface{}
+
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// evaluateJsonnet evaluates a Jsonnet template, passing each input as the
+// external variable input.<name>.
+func evaluateJsonnet(name, data string, inputs map[string]interface{}) ([]string, error) {
+	vm := jsonnet.MakeVM()
+
+	for key, value := range inputs {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		vm.ExtCode("input."+key, string(encoded))
+	}
+
+	// A template evaluating to an array produces one document per element.
+	docs, err := vm.EvaluateAnonymousSnippetStream(name, data)
+	if err != nil {
+		doc, err := vm.EvaluateAnonymousSnippet(name, data)
+		if err != nil {
+			return nil, err
+		}
+		docs = []string{doc}
+	}
+
+	return docs, nil
+}
+
+// executeStarlark runs a Starlark template's main function with a context
+// holding the inputs, and returns the JSON encoded pipelines it returns.
+func executeStarlark(name, data string, inputs map[string]interface{}) ([]string, error) {
+	thread := &starlark.Thread{Name: name}
+
+	globals, err := starlark.ExecFile(thread, name, data, starlark.StringDict{
+		"struct": starlark.NewBuiltin("struct", starlarkstruct.Make),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	main, ok := globals["main"]
+	if !ok {
+		return nil, errors.New("Error: Starlark template does not define a main function")
+	}
+
+	input := make(starlark.StringDict, len(inputs))
+	for key, value := range inputs {
+		if input[key], err = toStarlark(value); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx := starlarkstruct.FromStringDict(starlark.String("context"), starlark.StringDict{
+		"build": starlarkstruct.FromStringDict(starlark.String("build"), nil),
+		"repo":  starlarkstruct.FromStringDict(starlark.String("repo"), nil),
+		"input": starlarkstruct.FromStringDict(starlark.String("input"), input),
+	})
+
+	result, err := starlark.Call(thread, main, starlark.Tuple{ctx}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pipelines []starlark.Value
+	switch v := result.(type) {
+	case *starlark.Dict:
+		pipelines = append(pipelines, v)
+	case *starlark.List:
+		for i := 0; i < v.Len(); i++ {
+			pipelines = append(pipelines, v.Index(i))
+		}
+	default:
+		return nil, fmt.Errorf("Error: Starlark main function must return a dict or a list of dicts, got %s", result.Type())
+	}
+
+	encode := starlarkjson.Module.Members["encode"]
+
+	docs := make([]string, 0, len(pipelines))
+	for _, pipeline := range pipelines {
+		doc, err := starlark.Call(thread, encode, starlark.Tuple{pipeline}, nil)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, string(doc.(starlark.String)))
+	}
+
+	return docs, nil
+}
+
+// toStarlark converts a decoded JSON value to a Starlark value.
+func toStarlark(value interface{}) (starlark.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return starlark.None, nil
+	case bool:
+		return starlark.Bool(v), nil
+	case string:
+		return starlark.String(v), nil
+	case float64:
+		if v == float64(int64(v)) {
+			return starlark.MakeInt64(int64(v)), nil
+		}
+		return starlark.Float(v), nil
+	case []interface{}:
+		list := make([]starlark.Value, 0, len(v))
+		for _, elem := range v {
+			converted, err := toStarlark(elem)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, converted)
+		}
+		return starlark.NewList(list), nil
+	case map[string]interface{}:
+		dict := starlark.NewDict(len(v))
+		for key, elem := range v {
+			converted, err := toStarlark(elem)
+			if err != nil {
+				return nil, err
+			}
+			if err := dict.SetKey(starlark.String(key), converted); err != nil {
+				return nil, err
+			}
+		}
+		return dict, nil
+	default:
+		return nil, fmt.Errorf("Error: Unsupported template input of type %T", value)
+	}
+}
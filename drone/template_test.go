@@ -0,0 +1,74 @@
+package drone
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTemplateData(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		data  string
+		valid bool
+	}{
+		{"base.yaml", "kind: pipeline\nname: default\n", true},
+		{"base.yml", "kind: pipeline\nsteps:\n- name: build\n  image: {{ .input.image }}\n", true},
+		{"base.yaml", "kind: pipeline\n  name: default\n", false},
+		{"base.yaml", "kind: {{ .input.kind\n", false},
+		{"base.jsonnet", "{ kind: 'pipeline', image: std.extVar('input.image') }", true},
+		{"base.jsonnet", "{ kind: 'pipeline', ", false},
+		{"base.jsonnet", "{ kind: error 'broken' }", false},
+		{"base.star", "def main(ctx):\n    return {'kind': 'pipeline'}\n", true},
+		{"base.starlark", "def main(ctx)\n    return {}\n", false},
+		{"base.txt", "kind: pipeline\n", false},
+	} {
+		err := validateTemplateData(tc.name, tc.data)
+		if tc.valid && err != nil {
+			t.Errorf("expected %s %q to be valid, got: %s", tc.name, tc.data, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("expected %s %q to be invalid", tc.name, tc.data)
+		}
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	inputs := map[string]interface{}{
+		"image": "golang",
+		"steps": []interface{}{"test", "build"},
+	}
+
+	for _, tc := range []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			"base.yaml",
+			"kind: pipeline\nsteps:\n{{ range .input.steps }}- name: {{ . }}\n  image: {{ $.input.image }}\n{{ end }}",
+			[]string{"- name: test\n  image: golang\n- name: build\n  image: golang\n"},
+		},
+		{
+			"base.jsonnet",
+			"[{ kind: 'pipeline', name: s, image: std.extVar('input.image') } for s in std.extVar('input.steps')]",
+			[]string{"---\n{", `"name": "test"`, `"name": "build"`, `"image": "golang"`},
+		},
+		{
+			"base.star",
+			"def main(ctx):\n    return [{'kind': 'pipeline', 'name': s, 'image': ctx.input.image} for s in ctx.input.steps]\n",
+			[]string{"---\n{", `"name":"test"`, `"name":"build"`, `"image":"golang"`},
+		},
+	} {
+		rendered, err := renderTemplate(tc.name, tc.data, inputs)
+		if err != nil {
+			t.Errorf("failed to render %s: %s", tc.name, err)
+			continue
+		}
+
+		for _, want := range tc.want {
+			if !strings.Contains(rendered, want) {
+				t.Errorf("expected %s to render %q, got:\n%s", tc.name, want, rendered)
+			}
+		}
+	}
+}
@@ -10,7 +10,7 @@ import (
 func ParseRepo(str string) (user, repo string, err error) {
 	parts := strings.Split(str, "/")
 
-	if len(parts) != 2 {
+	if len(parts) != 2 || hasEmpty(parts) {
 		err = fmt.Errorf("Error: Invalid repository (e.g. octocat/hello-world). REPO: %s", str)
 		return
 	}
@@ -23,38 +23,61 @@ func ParseRepo(str string) (user, repo string, err error) {
 func ParseId(str, example string) (user, repo, id string, err error) {
 	parts := strings.Split(str, "/")
 
-	if len(parts) < 3 {
+	if len(parts) != 3 || hasEmpty(parts) {
 		err = fmt.Errorf(
-			"Error: Invalid identity (e.g. octocat/hello-world/%s).",
+			"Error: Invalid identity (e.g. octocat/hello-world/%s). ID: %s",
 			example,
+			str,
 		)
 		return
 	}
 
 	user = parts[0]
 	repo = parts[1]
-
-	id = strings.Join(parts[2:], "/")
+	id = parts[2]
 
 	return
 }
 
 func ParseOrgId(str, example string) (organization, id string, err error) {
 	parts := strings.Split(str, "/")
-	if len(parts) < 2 {
+
+	if len(parts) != 2 || hasEmpty(parts) {
 		err = fmt.Errorf(
-			"Error: Invalid Organization Identity (e.g. octocat/%s)",
+			"Error: Invalid Organization Identity (e.g. octocat/%s). ID: %s",
 			example,
+			str,
 		)
 		return
 	}
 
 	organization = parts[0]
-	id = strings.Join(parts[1:], "/")
+	id = parts[1]
+
+	return
+}
+
+func ParseLogin(str string) (login string, err error) {
+	if strings.TrimSpace(str) == "" || strings.Contains(str, "/") {
+		err = fmt.Errorf("Error: Invalid login (e.g. octocat). LOGIN: %s", str)
+		return
+	}
 
+	login = str
 	return
 }
 
+// hasEmpty reports whether any of the ID parts is blank.
+func hasEmpty(parts []string) bool {
+	for _, part := range parts {
+		if strings.TrimSpace(part) == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func Bool(val bool) *bool {
 	return &val
 }
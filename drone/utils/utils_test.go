@@ -0,0 +1,63 @@
+package utils
+
+import "testing"
+
+func TestParseRepo(t *testing.T) {
+	user, repo, err := ParseRepo("octocat/hello-world")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if user != "octocat" || repo != "hello-world" {
+		t.Fatalf("unexpected result: %s %s", user, repo)
+	}
+
+	for _, str := range []string{"", "octocat", "octocat/", "/hello-world", "octocat/hello-world/extra"} {
+		if _, _, err := ParseRepo(str); err == nil {
+			t.Errorf("expected %q to be invalid", str)
+		}
+	}
+}
+
+func TestParseId(t *testing.T) {
+	user, repo, id, err := ParseId("octocat/hello-world/secret_name", "secret_name")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if user != "octocat" || repo != "hello-world" || id != "secret_name" {
+		t.Fatalf("unexpected result: %s %s %s", user, repo, id)
+	}
+
+	for _, str := range []string{"", "octocat/hello-world", "octocat/hello-world/", "octocat//secret_name", "octocat/hello-world/secret/name"} {
+		if _, _, _, err := ParseId(str, "secret_name"); err == nil {
+			t.Errorf("expected %q to be invalid", str)
+		}
+	}
+}
+
+func TestParseOrgId(t *testing.T) {
+	organization, id, err := ParseOrgId("octocat/secret_name", "secret_name")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if organization != "octocat" || id != "secret_name" {
+		t.Fatalf("unexpected result: %s %s", organization, id)
+	}
+
+	for _, str := range []string{"", "octocat", "octocat/", "/secret_name", "octocat/secret/name"} {
+		if _, _, err := ParseOrgId(str, "secret_name"); err == nil {
+			t.Errorf("expected %q to be invalid", str)
+		}
+	}
+}
+
+func TestParseLogin(t *testing.T) {
+	if _, err := ParseLogin("octocat"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, str := range []string{"", "octocat/hello-world"} {
+		if _, err := ParseLogin(str); err == nil {
+			t.Errorf("expected %q to be invalid", str)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+
+	"terraform-provider-drone/drone"
+)
+
+// export implements the export subcommand, which writes Terraform
+// configuration and import blocks for the objects on an existing Drone
+// server.
+func export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	server := flags.String("server", os.Getenv("DRONE_SERVER"), "Drone server address, defaults to DRONE_SERVER")
+	token := flags.String("token", os.Getenv("DRONE_TOKEN"), "Drone API token, defaults to DRONE_TOKEN")
+	source := flags.String("source", "all", "How repositories are listed: sync, list or all (admin only, also exports organization secrets, templates and users)")
+	out := flags.String("out", "", "File to write the configuration to, defaults to stdout")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	if *server == "" || *token == "" {
+		return errors.New("Error: a Drone server and token are required, set -server and -token or DRONE_SERVER and DRONE_TOKEN")
+	}
+
+	opts := drone.ExportOptions{
+		Server:   *server,
+		Token:    *token,
+		Source:   *source,
+		Warnings: os.Stderr,
+	}
+
+	if *out == "" {
+		return drone.Export(os.Stdout, opts)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+
+	if err := drone.Export(f, opts); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
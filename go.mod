@@ -3,9 +3,15 @@ module terraform-provider-drone
 go 1.16
 
 require (
+	github.com/Masterminds/sprig/v3 v3.2.2
 	github.com/drone/drone-go v1.7.1
+	github.com/google/go-jsonnet v0.19.1
+	github.com/hashicorp/hcl/v2 v2.13.0
 	github.com/hashicorp/terraform-plugin-docs v0.13.0
 	github.com/hashicorp/terraform-plugin-sdk/v2 v2.20.0
 	github.com/jackspirou/syscerts v0.0.0-20160531025014-b68f5469dff1
+	github.com/zclconf/go-cty v1.10.0
+	go.starlark.net v0.0.0-20230302034142-4b1e35fe2254
 	golang.org/x/oauth2 v0.0.0-20220608161450-d0670ef3b1eb
+	gopkg.in/yaml.v3 v3.0.1
 )